@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/GitIBB/pursuit/internal/api"
 	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/GitIBB/pursuit/internal/mail"
+	"github.com/GitIBB/pursuit/internal/scheduler"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -17,10 +21,10 @@ import (
 // headers to the response.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "https://localhost:5173")           // Adjust origin as needed
-		w.Header().Set("Access-Control-Allow-Credentials", "true")                        // Allow credentials to be included in requests / cookies
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS") // Allow specific HTTP methods
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")     // Allow specific headers
+		w.Header().Set("Access-Control-Allow-Origin", "https://localhost:5173")                  // Adjust origin as needed
+		w.Header().Set("Access-Control-Allow-Credentials", "true")                               // Allow credentials to be included in requests / cookies
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS") // Allow specific HTTP methods
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")            // Allow specific headers
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -31,7 +35,8 @@ func corsMiddleware(next http.Handler) http.Handler {
 }
 
 func main() {
-	const port = "8080" // sets port for the server to listen on
+	const port = "8080"                 // sets port for the server to listen on
+	const publishInterval = time.Minute // how often scheduled articles are checked for publishing
 
 	godotenv.Load("../../.env")  // Load environment variables from .env file
 	dbURL := os.Getenv("DB_URL") // Get database URL from environment variable
@@ -49,13 +54,32 @@ func main() {
 		log.Fatal("JWT_SECRET NOT SET")
 	}
 
+	baseURL := os.Getenv("BASE_URL") // Get the public URL of the site from environment variable, mails link to it
+	if baseURL == "" {
+		log.Fatal("BASE_URL NOT SET")
+	}
+
 	dbCon, err := sql.Open("postgres", dbURL) // Open a connection to the PostgreSQL database using the provided URL
 	if err != nil {
 		log.Fatal("Error opening database connection:", err)
 	}
 	dbQueries := database.New(dbCon) // Create a new database connection using the provided URL
 
-	apiCfg := api.NewAPIConfig(dbQueries, platform, jwtSecret) // Create a new instance of the apiConfig struct
+	scheduler.StartPublisher(context.Background(), dbQueries, publishInterval) // Start publishing scheduled articles in the background
+
+	apiCfg := api.NewAPIConfig(dbCon, platform, jwtSecret)                            // Create a new instance of the apiConfig struct
+	apiCfg.SetBaseURL(baseURL)                                                        // Public URL of the site for mails, feeds and sitemaps
+	apiCfg.SetRequireVerifiedEmail(os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true") // Block article creation until the author confirmed their address
+
+	if mailDir := os.Getenv("MAIL_DIR"); mailDir != "" { // Write outgoing mail to files instead of the log
+		mailer, err := mail.NewFileSender(mailDir)
+		if err != nil {
+			log.Fatal("Error creating mail directory:", err)
+		}
+		apiCfg.SetMailer(mailer)
+	} else if platform != "dev" { // The log only takes the mail in development, it would leak working reset links
+		log.Fatal("MAIL_DIR NOT SET")
+	}
 
 	mux := http.NewServeMux()      // Create a new HTTP server mux (router)
 	apiCfg.SetupRoutes(mux)        // Setup routes for the API using the provided configuration
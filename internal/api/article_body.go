@@ -0,0 +1,270 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ArticleBodyVersion is the version of the block based article body, bodies without a version use the legacy
+// headers/content/images shape and are converted to blocks when they are read
+const ArticleBodyVersion = 2
+
+const (
+	BlockTypeHeading   = "heading"
+	BlockTypeParagraph = "paragraph"
+	BlockTypeImage     = "image"
+	BlockTypeQuote     = "quote"
+	BlockTypeCode      = "code"
+	BlockTypeList      = "list"
+	BlockTypeEmbed     = "embed"
+)
+
+const (
+	maxArticleBlocks      = 500   // most blocks a single article body can have
+	maxBlockTextLength    = 20000 // longest text, code or list item in a block
+	maxHeadingLength      = 200   // longest heading text
+	maxCodeLanguageLength = 30    // longest language name of a code block
+)
+
+type ArticleBlock struct { // struct to hold a single block of an article body, only the fields of its type are set
+	Type     string   `json:"type"`               // heading, paragraph, image, quote, code, list or embed
+	Text     string   `json:"text,omitempty"`     // heading, paragraph and quote text
+	Level    int      `json:"level,omitempty"`    // heading level, 1 to 6
+	URL      string   `json:"url,omitempty"`      // image and embed source
+	Alt      string   `json:"alt,omitempty"`      // image alternative text
+	Caption  string   `json:"caption,omitempty"`  // image and embed caption
+	Cite     string   `json:"cite,omitempty"`     // quote source
+	Language string   `json:"language,omitempty"` // code language, e.g. go
+	Code     string   `json:"code,omitempty"`     // code block contents
+	Ordered  bool     `json:"ordered,omitempty"`  // numbered instead of bulleted list
+	Items    []string `json:"items,omitempty"`    // list items
+}
+
+// legacyArticleBody is the shape article bodies had before they were made of blocks
+type legacyArticleBody struct {
+	Headers map[string]string          `json:"headers"`
+	Content map[string]json.RawMessage `json:"content"`
+	Images  map[string]string          `json:"images"`
+}
+
+// legacySectionOrder is the order the known sections of a legacy body are converted in, other sections follow by name
+var legacySectionOrder = []string{"introduction", "mainBody", "conclusion"}
+
+// UnmarshalJSON reads both the versioned block body and the legacy headers/content/images shape,
+// legacy bodies are converted to blocks
+func (body *ArticleBody) UnmarshalJSON(data []byte) error {
+	var probe struct {
+		Version *int            `json:"version"`
+		Blocks  json.RawMessage `json:"blocks"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	if probe.Version == nil && probe.Blocks == nil {
+		var legacy legacyArticleBody
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return err
+		}
+		*body = legacy.blocks()
+		return nil
+	}
+
+	if probe.Version != nil && *probe.Version != ArticleBodyVersion {
+		return fmt.Errorf("unsupported article body version %d", *probe.Version)
+	}
+	blocks := []ArticleBlock{}
+	if probe.Blocks != nil {
+		if err := json.Unmarshal(probe.Blocks, &blocks); err != nil {
+			return err
+		}
+	}
+	*body = ArticleBody{Version: ArticleBodyVersion, Blocks: blocks}
+	return nil
+}
+
+// blocks converts a legacy body section by section, each section becomes its header, its image and its content,
+// in the same way the 019_article_blocks migration converts the stored bodies
+func (legacy legacyArticleBody) blocks() ArticleBody {
+	sections := []string{}
+	addSection := func(section string) {
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+	for section := range legacy.Headers {
+		addSection(section)
+	}
+	for section := range legacy.Content {
+		addSection(section)
+	}
+	for section := range legacy.Images {
+		addSection(section)
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		a, b := slices.Index(legacySectionOrder, sections[i]), slices.Index(legacySectionOrder, sections[j])
+		switch {
+		case a >= 0 && b >= 0:
+			return a < b
+		case a >= 0 || b >= 0:
+			return a >= 0 // known sections go first
+		default:
+			return sections[i] < sections[j]
+		}
+	})
+
+	body := ArticleBody{Version: ArticleBodyVersion, Blocks: []ArticleBlock{}}
+	for _, section := range sections {
+		if header := legacy.Headers[section]; header != "" {
+			body.Blocks = append(body.Blocks, ArticleBlock{Type: BlockTypeHeading, Level: 2, Text: header})
+		}
+		if image := legacy.Images[section]; image != "" {
+			body.Blocks = append(body.Blocks, ArticleBlock{Type: BlockTypeImage, URL: image})
+		}
+		for _, paragraph := range legacyParagraphs(legacy.Content[section]) {
+			body.Blocks = append(body.Blocks, ArticleBlock{Type: BlockTypeParagraph, Text: paragraph})
+		}
+	}
+	return body
+}
+
+// legacyParagraphs returns the paragraphs of a legacy content section, a string is a single paragraph and every
+// element of an array is one, anything else is kept as its JSON text
+func legacyParagraphs(content json.RawMessage) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(content, &values); err != nil {
+		values = []json.RawMessage{content}
+	}
+
+	paragraphs := []string{}
+	for _, value := range values {
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			text = string(value)
+		}
+		if text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	return paragraphs
+}
+
+// validateBlock checks a block against the rules of its type and returns it with only the fields of that type set,
+// its text may only have the markup inlineHTMLPolicy allows
+func validateBlock(block ArticleBlock) (ArticleBlock, error) {
+	cleaned := ArticleBlock{Type: block.Type}
+	switch block.Type {
+	case BlockTypeHeading:
+		if block.Level == 0 {
+			block.Level = 2
+		}
+		if block.Level < 1 || block.Level > 6 {
+			return cleaned, errors.New("heading level must be between 1 and 6")
+		}
+		text, err := checkInlineHTML("heading text", block.Text)
+		if err != nil {
+			return cleaned, err
+		}
+		block.Text = text
+		if err := validateBlockText("heading text", block.Text, maxHeadingLength); err != nil {
+			return cleaned, err
+		}
+		cleaned.Level, cleaned.Text = block.Level, block.Text
+	case BlockTypeParagraph:
+		text, err := checkInlineHTML("paragraph text", block.Text)
+		if err != nil {
+			return cleaned, err
+		}
+		block.Text = text
+		if err := validateBlockText("paragraph text", block.Text, maxBlockTextLength); err != nil {
+			return cleaned, err
+		}
+		cleaned.Text = block.Text
+	case BlockTypeImage:
+		if err := validateBlockURL("image url", block.URL, true); err != nil {
+			return cleaned, err
+		}
+		cleaned.URL, cleaned.Alt, cleaned.Caption = block.URL, block.Alt, block.Caption
+	case BlockTypeQuote:
+		text, err := checkInlineHTML("quote text", block.Text)
+		if err != nil {
+			return cleaned, err
+		}
+		block.Text = text
+		if err := validateBlockText("quote text", block.Text, maxBlockTextLength); err != nil {
+			return cleaned, err
+		}
+		cleaned.Text, cleaned.Cite = block.Text, block.Cite
+	case BlockTypeCode:
+		if err := validateBlockText("code", block.Code, maxBlockTextLength); err != nil {
+			return cleaned, err
+		}
+		if len(block.Language) > maxCodeLanguageLength || strings.ContainsAny(block.Language, " \t\n") {
+			return cleaned, errors.New("code language must be a single word")
+		}
+		cleaned.Code, cleaned.Language = block.Code, block.Language
+	case BlockTypeList:
+		if len(block.Items) == 0 {
+			return cleaned, errors.New("list needs at least one item")
+		}
+		cleaned.Items, cleaned.Ordered = make([]string, len(block.Items)), block.Ordered
+		for i, item := range block.Items {
+			text, err := checkInlineHTML("list item", item)
+			if err != nil {
+				return cleaned, err
+			}
+			cleaned.Items[i] = text
+			if err := validateBlockText("list item", cleaned.Items[i], maxBlockTextLength); err != nil {
+				return cleaned, err
+			}
+		}
+	case BlockTypeEmbed:
+		if err := validateBlockURL("embed url", block.URL, false); err != nil {
+			return cleaned, err
+		}
+		cleaned.URL, cleaned.Caption = block.URL, block.Caption
+	default:
+		return cleaned, fmt.Errorf("unknown block type %q", block.Type)
+	}
+	return cleaned, nil
+}
+
+// validateBlockText checks that a text field of a block is set and not too long
+func validateBlockText(field, text string, maxLength int) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New(field + " is required")
+	}
+	if len([]rune(text)) > maxLength {
+		return fmt.Errorf("%s can be at most %d characters", field, maxLength)
+	}
+	return nil
+}
+
+// validateBlockURL checks that a URL field of a block is an http(s) URL, or a path on this server when local is true
+func validateBlockURL(field, rawURL string, local bool) error {
+	if rawURL == "" {
+		return errors.New(field + " is required")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%s is invalid: %w", field, err)
+	}
+	if parsed.Scheme == "http" || parsed.Scheme == "https" {
+		if parsed.Host == "" {
+			return errors.New(field + " must include a host")
+		}
+		return nil
+	}
+	if local && parsed.Scheme == "" && parsed.Host == "" && strings.HasPrefix(parsed.Path, "/") {
+		return nil
+	}
+	return errors.New(field + " must be an http or https URL")
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArticleBodyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ArticleBody
+		wantErr bool
+	}{
+		{
+			name: "Block body",
+			data: `{"version": 2, "blocks": [{"type": "paragraph", "text": "Hello"}]}`,
+			want: ArticleBody{Version: ArticleBodyVersion, Blocks: []ArticleBlock{{Type: BlockTypeParagraph, Text: "Hello"}}},
+		},
+		{
+			name: "Block body without blocks",
+			data: `{"version": 2}`,
+			want: ArticleBody{Version: ArticleBodyVersion, Blocks: []ArticleBlock{}},
+		},
+		{
+			name:    "Unknown version",
+			data:    `{"version": 3, "blocks": []}`,
+			wantErr: true,
+		},
+		{
+			name: "Legacy sections in order",
+			data: `{
+				"headers": {"conclusion": "Wrapping up", "introduction": "Intro", "extra": "Extra"},
+				"content": {"conclusion": "Bye", "introduction": "Hi", "mainBody": ["First", "", "Second"], "extra": {"a": 1}},
+				"images": {"mainBody": "/api/uploads/main.png"}
+			}`,
+			want: ArticleBody{Version: ArticleBodyVersion, Blocks: []ArticleBlock{
+				{Type: BlockTypeHeading, Level: 2, Text: "Intro"},
+				{Type: BlockTypeParagraph, Text: "Hi"},
+				{Type: BlockTypeImage, URL: "/api/uploads/main.png"},
+				{Type: BlockTypeParagraph, Text: "First"},
+				{Type: BlockTypeParagraph, Text: "Second"},
+				{Type: BlockTypeHeading, Level: 2, Text: "Wrapping up"},
+				{Type: BlockTypeParagraph, Text: "Bye"},
+				{Type: BlockTypeHeading, Level: 2, Text: "Extra"},
+				{Type: BlockTypeParagraph, Text: `{"a": 1}`},
+			}},
+		},
+		{
+			name: "Empty legacy body",
+			data: `{"headers": {}, "content": {}, "images": {}}`,
+			want: ArticleBody{Version: ArticleBodyVersion, Blocks: []ArticleBlock{}},
+		},
+		{
+			name:    "Legacy body with the wrong shape",
+			data:    `{"headers": ["Intro"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ArticleBody
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("json.Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   ArticleBlock
+		want    ArticleBlock
+		wantErr bool
+	}{
+		{
+			name:  "Heading level defaults to 2",
+			block: ArticleBlock{Type: BlockTypeHeading, Text: " Installing Go "},
+			want:  ArticleBlock{Type: BlockTypeHeading, Level: 2, Text: "Installing Go"},
+		},
+		{
+			name:    "Heading level out of range",
+			block:   ArticleBlock{Type: BlockTypeHeading, Level: 7, Text: "Installing Go"},
+			wantErr: true,
+		},
+		{
+			name:    "Heading too long",
+			block:   ArticleBlock{Type: BlockTypeHeading, Text: strings.Repeat("a", maxHeadingLength+1)},
+			wantErr: true,
+		},
+		{
+			name:  "Fields of other types are dropped",
+			block: ArticleBlock{Type: BlockTypeParagraph, Text: "Hello", URL: "https://example.com", Items: []string{"One"}},
+			want:  ArticleBlock{Type: BlockTypeParagraph, Text: "Hello"},
+		},
+		{
+			name:    "Paragraph without text",
+			block:   ArticleBlock{Type: BlockTypeParagraph, Text: "  "},
+			wantErr: true,
+		},
+		{
+			name:  "Local image",
+			block: ArticleBlock{Type: BlockTypeImage, URL: "/api/uploads/go.png", Alt: "Gopher", Caption: "Our mascot"},
+			want:  ArticleBlock{Type: BlockTypeImage, URL: "/api/uploads/go.png", Alt: "Gopher", Caption: "Our mascot"},
+		},
+		{
+			name:    "Image URL without host",
+			block:   ArticleBlock{Type: BlockTypeImage, URL: "https:///go.png"},
+			wantErr: true,
+		},
+		{
+			name:    "Local embed",
+			block:   ArticleBlock{Type: BlockTypeEmbed, URL: "/api/uploads/talk.mp4"},
+			wantErr: true,
+		},
+		{
+			name:  "Quote with cite",
+			block: ArticleBlock{Type: BlockTypeQuote, Text: "Clear is better than clever.", Cite: "Rob Pike"},
+			want:  ArticleBlock{Type: BlockTypeQuote, Text: "Clear is better than clever.", Cite: "Rob Pike"},
+		},
+		{
+			name:  "Code is kept as written",
+			block: ArticleBlock{Type: BlockTypeCode, Language: "go", Code: "\tif a < b {\n\t}\n"},
+			want:  ArticleBlock{Type: BlockTypeCode, Language: "go", Code: "\tif a < b {\n\t}\n"},
+		},
+		{
+			name:    "Code language with spaces",
+			block:   ArticleBlock{Type: BlockTypeCode, Language: "go lang", Code: "x"},
+			wantErr: true,
+		},
+		{
+			name:    "List without items",
+			block:   ArticleBlock{Type: BlockTypeList, Ordered: true},
+			wantErr: true,
+		},
+		{
+			name:    "List with an empty item",
+			block:   ArticleBlock{Type: BlockTypeList, Items: []string{"One", ""}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown type",
+			block:   ArticleBlock{Type: "table"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateBlock(tt.block)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateBlock() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
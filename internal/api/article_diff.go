@@ -0,0 +1,82 @@
+package api
+
+import (
+	"reflect"
+)
+
+type FieldChange struct { // struct to hold a change to a single article field
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type BlockChange struct { // struct to hold a change to a single block of the article body
+	Change    string        `json:"change"`               // added, removed or modified
+	FromIndex *int          `json:"from_index,omitempty"` // position of the block in the older body, unset for added blocks
+	ToIndex   *int          `json:"to_index,omitempty"`   // position of the block in the newer body, unset for removed blocks
+	From      *ArticleBlock `json:"from,omitempty"`
+	To        *ArticleBlock `json:"to,omitempty"`
+}
+
+// diffArticleBodies compares the blocks of two article bodies in order. Blocks both bodies have in common are
+// matched up first, the blocks in between are reported as modified pairwise and as added or removed when one
+// side has more of them.
+func diffArticleBodies(from, to ArticleBody) []BlockChange {
+	a, b := from.Blocks, to.Blocks
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if reflect.DeepEqual(a[i], b[j]) {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	changes := []BlockChange{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if i < len(a) && j < len(b) && reflect.DeepEqual(a[i], b[j]) {
+			i, j = i+1, j+1
+			continue
+		}
+
+		// Collect the run of unmatched blocks on both sides up to the next common block
+		removed, added := []int{}, []int{}
+		for i < len(a) || j < len(b) {
+			if i < len(a) && j < len(b) && reflect.DeepEqual(a[i], b[j]) {
+				break
+			}
+			if j == len(b) || (i < len(a) && common[i+1][j] >= common[i][j+1]) {
+				removed = append(removed, i)
+				i++
+			} else {
+				added = append(added, j)
+				j++
+			}
+		}
+
+		for k := 0; k < max(len(removed), len(added)); k++ {
+			change := BlockChange{}
+			if k < len(removed) {
+				change.Change = "removed"
+				change.FromIndex, change.From = &removed[k], &a[removed[k]]
+			}
+			if k < len(added) {
+				change.Change = "added"
+				change.ToIndex, change.To = &added[k], &b[added[k]]
+			}
+			if k < len(removed) && k < len(added) {
+				change.Change = "modified"
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
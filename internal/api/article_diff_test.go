@@ -0,0 +1,93 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiffArticleBodies(t *testing.T) {
+	a := ArticleBlock{Type: BlockTypeParagraph, Text: "A"}
+	b := ArticleBlock{Type: BlockTypeParagraph, Text: "B"}
+	c := ArticleBlock{Type: BlockTypeParagraph, Text: "C"}
+	d := ArticleBlock{Type: BlockTypeParagraph, Text: "D"}
+
+	tests := []struct {
+		name string
+		from []ArticleBlock
+		to   []ArticleBlock
+		want []string // change, from index and to index of every change, - for an unset index
+	}{
+		{
+			name: "Same blocks",
+			from: []ArticleBlock{a, b},
+			to:   []ArticleBlock{a, b},
+			want: []string{},
+		},
+		{
+			name: "Block added in the middle",
+			from: []ArticleBlock{a, c},
+			to:   []ArticleBlock{a, b, c},
+			want: []string{"added - 1"},
+		},
+		{
+			name: "Block removed at the start",
+			from: []ArticleBlock{a, b, c},
+			to:   []ArticleBlock{b, c},
+			want: []string{"removed 0 -"},
+		},
+		{
+			name: "Block modified between common blocks",
+			from: []ArticleBlock{a, b, c},
+			to:   []ArticleBlock{a, d, c},
+			want: []string{"modified 1 1"},
+		},
+		{
+			name: "More blocks replaced than added",
+			from: []ArticleBlock{a, b, c},
+			to:   []ArticleBlock{d},
+			want: []string{"modified 0 0", "removed 1 -", "removed 2 -"},
+		},
+		{
+			name: "Block moved",
+			from: []ArticleBlock{a, b, c},
+			to:   []ArticleBlock{b, c, a},
+			want: []string{"removed 0 -", "added - 2"},
+		},
+		{
+			name: "Empty bodies",
+			from: []ArticleBlock{},
+			to:   []ArticleBlock{a},
+			want: []string{"added - 0"},
+		},
+	}
+
+	index := func(i *int) string {
+		if i == nil {
+			return "-"
+		}
+		return strconv.Itoa(*i)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := diffArticleBodies(ArticleBody{Blocks: tt.from}, ArticleBody{Blocks: tt.to})
+			got := []string{}
+			for _, change := range changes {
+				got = append(got, change.Change+" "+index(change.FromIndex)+" "+index(change.ToIndex))
+				if (change.From != nil) != (change.FromIndex != nil) || (change.To != nil) != (change.ToIndex != nil) {
+					t.Errorf("change %+v has a block without its index or the other way around", change)
+				}
+				if change.FromIndex != nil && change.From.Text != tt.from[*change.FromIndex].Text {
+					t.Errorf("change %+v has the wrong older block", change)
+				}
+				if change.ToIndex != nil && change.To.Text != tt.to[*change.ToIndex].Text {
+					t.Errorf("change %+v has the wrong newer block", change)
+				}
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("diffArticleBodies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
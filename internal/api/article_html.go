@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// The text of heading, paragraph and quote blocks and of list items is inline HTML limited to inlineHTMLPolicy,
+// everything else in a block is plain text. Text is stored as it was written, & < > and quotes are only escaped when
+// the body is rendered as HTML. Saving text with other markup fails, and such text in bodies stored before the policy
+// existed is sanitized when it is read.
+var (
+	inlineHTMLPolicy  = newInlineHTMLPolicy()
+	articleHTMLPolicy = newArticleHTMLPolicy()
+	plainTextPolicy   = bluemonday.StrictPolicy()
+)
+
+// maxSummaryLength is the number of characters after which articleSummary cuts off the text
+const maxSummaryLength = 300
+
+// newInlineHTMLPolicy allows text formatting and links
+func newInlineHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("b", "strong", "i", "em", "u", "s", "del", "code", "sub", "sup", "mark", "br")
+	policy.AllowAttrs("href", "title").OnElements("a")
+	policy.AllowStandardURLs()           // http, https and mailto links, and relative ones
+	policy.RequireNoFollowOnLinks(false) // AllowStandardURLs turns it on, the rendered body gets it instead
+	return policy
+}
+
+// newArticleHTMLPolicy allows the inline elements and the elements renderArticleHTML writes for the blocks,
+// it is applied to the rendered body as a whole and gives links rel="nofollow"
+func newArticleHTMLPolicy() *bluemonday.Policy {
+	policy := newInlineHTMLPolicy()
+	policy.RequireNoFollowOnLinks(true)
+	policy.AllowElements("p", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "footer", "cite", "pre", "ul", "ol", "li", "figure", "figcaption")
+	policy.AllowAttrs("src", "alt").OnElements("img")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^embed$`)).OnElements("figure")
+	return policy
+}
+
+// checkInlineHTML returns block text without surrounding whitespace, or an error when it has markup inlineHTMLPolicy
+// does not allow. The sanitizer escapes & < > and quotes in the text it keeps, so both sides are compared unescaped.
+func checkInlineHTML(field, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if html.UnescapeString(inlineHTMLPolicy.Sanitize(text)) != html.UnescapeString(text) {
+		return text, errors.New(field + " contains markup that is not allowed")
+	}
+	return text, nil
+}
+
+// cleanInlineHTML returns block text as it is stored when it only has allowed markup, and sanitized otherwise
+func cleanInlineHTML(text string) string {
+	if _, err := checkInlineHTML("text", text); err != nil {
+		return strings.TrimSpace(inlineHTMLPolicy.Sanitize(text))
+	}
+	return text
+}
+
+// decodeArticleBody reads a stored article body, in either the block or the legacy shape, and sanitizes text
+// that has markup which is not allowed
+func decodeArticleBody(data json.RawMessage) (ArticleBody, error) {
+	var body ArticleBody
+	if err := json.Unmarshal(data, &body); err != nil {
+		return body, err
+	}
+	for i, block := range body.Blocks {
+		block.Text = cleanInlineHTML(block.Text)
+		for j, item := range block.Items {
+			block.Items[j] = cleanInlineHTML(item)
+		}
+		body.Blocks[i] = block
+	}
+	return body, nil
+}
+
+// renderArticleHTML turns an article body into sanitized HTML, one element per block
+func renderArticleHTML(body ArticleBody) string {
+	var b strings.Builder
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case BlockTypeHeading:
+			tag := "h" + strconv.Itoa(min(max(block.Level, 1), 6))
+			b.WriteString("<" + tag + ">" + block.Text + "</" + tag + ">")
+		case BlockTypeParagraph:
+			b.WriteString("<p>" + strings.ReplaceAll(block.Text, "\n", "<br>") + "</p>")
+		case BlockTypeImage:
+			b.WriteString(`<figure><img src="` + html.EscapeString(block.URL) + `" alt="` + html.EscapeString(block.Alt) + `">`)
+			if block.Caption != "" {
+				b.WriteString("<figcaption>" + html.EscapeString(block.Caption) + "</figcaption>")
+			}
+			b.WriteString("</figure>")
+		case BlockTypeQuote:
+			b.WriteString("<blockquote><p>" + strings.ReplaceAll(block.Text, "\n", "<br>") + "</p>")
+			if block.Cite != "" {
+				b.WriteString("<footer>— <cite>" + html.EscapeString(block.Cite) + "</cite></footer>")
+			}
+			b.WriteString("</blockquote>")
+		case BlockTypeCode:
+			b.WriteString("<pre><code")
+			if block.Language != "" {
+				b.WriteString(` class="language-` + html.EscapeString(block.Language) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(block.Code) + "</code></pre>")
+		case BlockTypeList:
+			tag := "ul"
+			if block.Ordered {
+				tag = "ol"
+			}
+			b.WriteString("<" + tag + ">")
+			for _, item := range block.Items {
+				b.WriteString("<li>" + strings.ReplaceAll(item, "\n", "<br>") + "</li>")
+			}
+			b.WriteString("</" + tag + ">")
+		case BlockTypeEmbed:
+			label := block.Caption
+			if label == "" {
+				label = block.URL
+			}
+			b.WriteString(`<figure class="embed"><a href="` + html.EscapeString(block.URL) + `">` + html.EscapeString(label) + "</a></figure>")
+		}
+		b.WriteString("\n")
+	}
+	return articleHTMLPolicy.Sanitize(b.String())
+}
+
+// articleSummary returns the plain text of the first paragraphs of a body, cut off at a word boundary
+// once it is longer than maxSummaryLength characters
+func articleSummary(body ArticleBody) string {
+	paragraphs := []string{}
+	length := 0
+	for _, block := range body.Blocks {
+		if block.Type != BlockTypeParagraph || length > maxSummaryLength {
+			continue
+		}
+		text := strings.Join(strings.Fields(html.UnescapeString(plainTextPolicy.Sanitize(block.Text))), " ")
+		paragraphs = append(paragraphs, text)
+		length += len([]rune(text)) + 1
+	}
+
+	summary := []rune(strings.Join(paragraphs, " "))
+	if len(summary) <= maxSummaryLength {
+		return string(summary)
+	}
+	cut := string(summary[:maxSummaryLength])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
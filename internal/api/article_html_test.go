@@ -0,0 +1,104 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderArticleHTML(t *testing.T) {
+	body := ArticleBody{Version: ArticleBodyVersion, Blocks: []ArticleBlock{
+		{Type: BlockTypeHeading, Level: 2, Text: "Installing <em>Go</em>"},
+		{Type: BlockTypeParagraph, Text: "First line\nsecond line"},
+		{Type: BlockTypeParagraph, Text: `AT&T said "a < b" and <a href="https://go.dev">linked</a>`},
+		{Type: BlockTypeImage, URL: "/api/uploads/go.png", Alt: `The "gopher"`, Caption: "<b>not bold</b>"},
+		{Type: BlockTypeQuote, Text: "Clear is better than clever.", Cite: "Rob Pike"},
+		{Type: BlockTypeCode, Language: "go", Code: "if a < b {\n}"},
+		{Type: BlockTypeList, Ordered: true, Items: []string{"One", "Two"}},
+		{Type: BlockTypeEmbed, URL: "https://example.com/talk"},
+	}}
+	want := strings.Join([]string{
+		"<h2>Installing <em>Go</em></h2>",
+		"<p>First line<br>second line</p>",
+		`<p>AT&amp;T said &#34;a &lt; b&#34; and <a href="https://go.dev" rel="nofollow">linked</a></p>`,
+		`<figure><img src="/api/uploads/go.png" alt="The &#34;gopher&#34;"><figcaption>&lt;b&gt;not bold&lt;/b&gt;</figcaption></figure>`,
+		"<blockquote><p>Clear is better than clever.</p><footer>— <cite>Rob Pike</cite></footer></blockquote>",
+		`<pre><code class="language-go">if a &lt; b {` + "\n" + `}</code></pre>`,
+		"<ol><li>One</li><li>Two</li></ol>",
+		`<figure class="embed"><a href="https://example.com/talk" rel="nofollow">https://example.com/talk</a></figure>`,
+		"",
+	}, "\n")
+
+	if got := renderArticleHTML(body); got != want {
+		t.Errorf("renderArticleHTML() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestValidateArticleChecksHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   ArticleBlock
+		want    ArticleBlock
+		wantErr bool
+	}{
+		{
+			name:  "Allowed formatting is kept",
+			block: ArticleBlock{Type: BlockTypeParagraph, Text: `<strong>Bold</strong> and <a href="https://go.dev">a link</a>`},
+			want:  ArticleBlock{Type: BlockTypeParagraph, Text: `<strong>Bold</strong> and <a href="https://go.dev">a link</a>`},
+		},
+		{
+			name:  "Text is stored as written",
+			block: ArticleBlock{Type: BlockTypeParagraph, Text: ` AT&T said "a < b", it's fine `},
+			want:  ArticleBlock{Type: BlockTypeParagraph, Text: `AT&T said "a < b", it's fine`},
+		},
+		{
+			name:  "Entities are stored as written",
+			block: ArticleBlock{Type: BlockTypeList, Items: []string{"&lt;script&gt; &amp; more"}},
+			want:  ArticleBlock{Type: BlockTypeList, Items: []string{"&lt;script&gt; &amp; more"}},
+		},
+		{
+			name:    "Scripts are rejected",
+			block:   ArticleBlock{Type: BlockTypeParagraph, Text: `Hello<script>alert(1)</script>`},
+			wantErr: true,
+		},
+		{
+			name:    "Event handlers are rejected",
+			block:   ArticleBlock{Type: BlockTypeQuote, Text: `<em onclick="alert(1)">Hi</em>`},
+			wantErr: true,
+		},
+		{
+			name:    "Javascript links are rejected",
+			block:   ArticleBlock{Type: BlockTypeQuote, Text: `<a href="javascript:alert(1)">there</a>`},
+			wantErr: true,
+		},
+		{
+			name:    "List items are checked",
+			block:   ArticleBlock{Type: BlockTypeList, Items: []string{`<img src=x onerror=alert(1)>One`}},
+			wantErr: true,
+		},
+		{
+			name:    "Text that is only markup is rejected",
+			block:   ArticleBlock{Type: BlockTypeHeading, Level: 2, Text: `<iframe src="https://example.com"></iframe>`},
+			wantErr: true,
+		},
+		{
+			name:    "Image URLs must be http(s) or local",
+			block:   ArticleBlock{Type: BlockTypeImage, URL: "javascript:alert(1)"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateArticle(ArticleBody{Blocks: []ArticleBlock{tt.block}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateArticle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotBlock := got.Blocks[0]; gotBlock.Text != tt.want.Text || strings.Join(gotBlock.Items, "|") != strings.Join(tt.want.Items, "|") {
+				t.Errorf("validateArticle() block = %+v, want %+v", gotBlock, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// markdownArticle is an article as it is written in Markdown, the fields other than the body come from the front matter
+//
+//	---
+//	title: Getting started with Go
+//	category_id: 6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c
+//	tags: go, beginners
+//	---
+//
+//	## Installing Go
+//
+//	Download the installer from the Go website.
+//
+// Without a title in the front matter a leading level 1 heading is used as the title.
+type markdownArticle struct {
+	Title      string
+	CategoryID uuid.UUID
+	ImageUrl   string
+	Status     string
+	PublishAt  *time.Time
+	Tags       []string
+	Body       ArticleBody
+}
+
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6}) (.*)$`)
+	markdownImagePattern   = regexp.MustCompile(`^!\[((?:[^\]\\]|\\.)*)\]\(([^\s()]+)(?: "((?:[^"\\]|\\.)*)")?\)$`)
+	markdownEmbedPattern   = regexp.MustCompile(`^@\[((?:[^\]\\]|\\.)*)\]\(([^\s()]+)\)$`)
+	markdownBulletPattern  = regexp.MustCompile(`^[-*+] (.*)$`)
+	markdownNumberPattern  = regexp.MustCompile(`^\d+[.)] (.*)$`)
+	markdownFencePattern   = regexp.MustCompile("^(`{3,})(.*)$")
+	markdownEscapePattern  = regexp.MustCompile(`\\(.)`)
+
+	// lines starting like this are escaped in paragraphs and quotes so they are not read as the start of another block
+	markdownBlockStartPattern = regexp.MustCompile("^(?:[#>*+!@`\\\\-]|— )")
+	markdownOrderedStart      = regexp.MustCompile(`^(\d+)([.)] )`)
+	markdownEscapedOrdered    = regexp.MustCompile(`^(\d+)\\([.)] )`)
+)
+
+// markdownQuoteCite starts the last line of a quote that names its source
+const markdownQuoteCite = "— "
+
+// parseMarkdownArticle reads an article written in Markdown, see markdownArticle for the format
+func parseMarkdownArticle(source string) (markdownArticle, error) {
+	article := markdownArticle{}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	// Read the front matter
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return article, errors.New("front matter is not closed with ---")
+		}
+		for _, line := range lines[1:end] {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return article, fmt.Errorf("front matter line %q is not a key: value pair", line)
+			}
+			if err := article.setFrontMatter(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+				return article, err
+			}
+		}
+		lines = lines[end+1:]
+	}
+
+	blocks := parseMarkdownBlocks(lines)
+
+	// A leading level 1 heading is the title unless the front matter has one
+	if article.Title == "" && len(blocks) > 0 && blocks[0].Type == BlockTypeHeading && blocks[0].Level == 1 {
+		article.Title = blocks[0].Text
+		blocks = blocks[1:]
+	}
+	if article.Title == "" {
+		return article, errors.New("title is required, set it in the front matter or start with a level 1 heading")
+	}
+
+	article.Body = ArticleBody{Version: ArticleBodyVersion, Blocks: blocks}
+	return article, nil
+}
+
+// setFrontMatter stores a single front matter value
+func (article *markdownArticle) setFrontMatter(key, value string) error {
+	switch key {
+	case "title":
+		article.Title = value
+	case "category_id":
+		categoryID, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid category_id: %w", err)
+		}
+		article.CategoryID = categoryID
+	case "image_url":
+		article.ImageUrl = value
+	case "status":
+		article.Status = value
+	case "publish_at":
+		publishAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid publish_at: %w", err)
+		}
+		article.PublishAt = &publishAt
+	case "tags":
+		article.Tags = []string{}
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				article.Tags = append(article.Tags, tag)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown front matter key %q", key)
+	}
+	return nil
+}
+
+// parseMarkdownBlocks reads the blocks of a Markdown body, blocks are separated by blank lines
+func parseMarkdownBlocks(lines []string) []ArticleBlock {
+	blocks := []ArticleBlock{}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			i++
+			continue
+		}
+
+		if match := markdownFencePattern.FindStringSubmatch(line); match != nil {
+			fence := match[1]
+			code := []string{}
+			for i++; i < len(lines); i++ {
+				closing := strings.TrimRight(lines[i], " ")
+				if strings.HasPrefix(closing, fence) && strings.Trim(closing, "`") == "" {
+					i++
+					break
+				}
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, ArticleBlock{Type: BlockTypeCode, Language: strings.TrimSpace(match[2]), Code: strings.Join(code, "\n")})
+			continue
+		}
+
+		if match := markdownHeadingPattern.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, ArticleBlock{Type: BlockTypeHeading, Level: len(match[1]), Text: strings.TrimSpace(match[2])})
+			i++
+			continue
+		}
+
+		if match := markdownImagePattern.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, ArticleBlock{Type: BlockTypeImage, Alt: unescapeMarkdown(match[1]), URL: match[2], Caption: unescapeMarkdown(match[3])})
+			i++
+			continue
+		}
+
+		if match := markdownEmbedPattern.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, ArticleBlock{Type: BlockTypeEmbed, Caption: unescapeMarkdown(match[1]), URL: match[2]})
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(line, ">") {
+			quote := []string{}
+			for ; i < len(lines) && strings.HasPrefix(lines[i], ">"); i++ {
+				quoteLine := strings.TrimPrefix(lines[i], ">")
+				quote = append(quote, strings.TrimPrefix(quoteLine, " "))
+			}
+			block := ArticleBlock{Type: BlockTypeQuote}
+			if last := quote[len(quote)-1]; len(quote) > 1 && strings.HasPrefix(last, markdownQuoteCite) {
+				block.Cite = strings.TrimPrefix(last, markdownQuoteCite)
+				quote = quote[:len(quote)-1]
+			}
+			block.Text = unescapeMarkdownLines(quote)
+			blocks = append(blocks, block)
+			continue
+		}
+
+		if markdownBulletPattern.MatchString(line) || markdownNumberPattern.MatchString(line) {
+			block := ArticleBlock{Type: BlockTypeList, Ordered: markdownNumberPattern.MatchString(line), Items: []string{}}
+			for ; i < len(lines); i++ {
+				if match := markdownBulletPattern.FindStringSubmatch(lines[i]); match != nil && !block.Ordered {
+					block.Items = append(block.Items, match[1])
+				} else if match := markdownNumberPattern.FindStringSubmatch(lines[i]); match != nil && block.Ordered {
+					block.Items = append(block.Items, match[1])
+				} else if strings.HasPrefix(lines[i], "  ") && strings.TrimSpace(lines[i]) != "" {
+					// continuation of the item, blank lines and lines starting with a backslash are escaped with one
+					continuation := strings.TrimPrefix(strings.TrimPrefix(lines[i], "  "), `\`)
+					block.Items[len(block.Items)-1] += "\n" + continuation
+				} else {
+					break
+				}
+			}
+			blocks = append(blocks, block)
+			continue
+		}
+
+		// Anything else is a paragraph running up to the next blank line, blank lines inside it are escaped
+		paragraph := []string{}
+		for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+			paragraph = append(paragraph, lines[i])
+		}
+		blocks = append(blocks, ArticleBlock{Type: BlockTypeParagraph, Text: unescapeMarkdownLines(paragraph)})
+	}
+	return blocks
+}
+
+// renderMarkdownArticle writes an article as Markdown with its title, category, image, status, publish time and tags in
+// the front matter
+func renderMarkdownArticle(article markdownArticle) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strings.ReplaceAll(article.Title, "\n", " "))
+	fmt.Fprintf(&b, "category_id: %s\n", article.CategoryID)
+	if article.ImageUrl != "" {
+		fmt.Fprintf(&b, "image_url: %s\n", article.ImageUrl)
+	}
+	if article.Status != "" {
+		fmt.Fprintf(&b, "status: %s\n", article.Status)
+	}
+	if article.PublishAt != nil {
+		fmt.Fprintf(&b, "publish_at: %s\n", article.PublishAt.Format(time.RFC3339))
+	}
+	if len(article.Tags) > 0 {
+		fmt.Fprintf(&b, "tags: %s\n", strings.Join(article.Tags, ", "))
+	}
+	b.WriteString("---\n")
+	for _, block := range article.Body.Blocks {
+		b.WriteString("\n")
+		b.WriteString(renderMarkdownBlock(block))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderMarkdownBlock writes a single block as Markdown, without the blank line that separates it from the next one
+func renderMarkdownBlock(block ArticleBlock) string {
+	switch block.Type {
+	case BlockTypeHeading:
+		return strings.Repeat("#", max(block.Level, 1)) + " " + strings.ReplaceAll(block.Text, "\n", " ")
+	case BlockTypeParagraph:
+		// a blank line would end the paragraph, so it is escaped like a line starting another block
+		lines := strings.Split(escapeMarkdownLines(block.Text), "\n")
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				lines[i] = `\` + line
+			}
+		}
+		return strings.Join(lines, "\n")
+	case BlockTypeImage:
+		image := "![" + escapeMarkdown(block.Alt, "]") + "](" + block.URL
+		if block.Caption != "" {
+			image += ` "` + escapeMarkdown(block.Caption, `"`) + `"`
+		}
+		return image + ")"
+	case BlockTypeEmbed:
+		return "@[" + escapeMarkdown(block.Caption, "]") + "](" + block.URL + ")"
+	case BlockTypeQuote:
+		lines := strings.Split(escapeMarkdownLines(block.Text), "\n")
+		if block.Cite != "" {
+			lines = append(lines, markdownQuoteCite+block.Cite)
+		}
+		for i, line := range lines {
+			if line == "" {
+				lines[i] = ">"
+			} else {
+				lines[i] = "> " + line
+			}
+		}
+		return strings.Join(lines, "\n")
+	case BlockTypeCode:
+		fence := "```"
+		for strings.Contains(block.Code, fence) {
+			fence += "`"
+		}
+		return fence + block.Language + "\n" + block.Code + "\n" + fence
+	case BlockTypeList:
+		items := make([]string, len(block.Items))
+		for i, item := range block.Items {
+			marker := "- "
+			if block.Ordered {
+				marker = strconv.Itoa(i+1) + ". "
+			}
+			// a blank line would end the list, so it is escaped, and so are lines starting with a backslash
+			lines := strings.Split(item, "\n")
+			for j, line := range lines[1:] {
+				if strings.TrimSpace(line) == "" || strings.HasPrefix(line, `\`) {
+					lines[j+1] = `\` + line
+				}
+			}
+			items[i] = marker + strings.Join(lines, "\n  ")
+		}
+		return strings.Join(items, "\n")
+	}
+	return ""
+}
+
+// escapeMarkdown puts a backslash in front of backslashes and the given special characters
+func escapeMarkdown(text, special string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r == '\\' || strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unescapeMarkdown removes the backslashes escapeMarkdown added
+func unescapeMarkdown(text string) string {
+	return markdownEscapePattern.ReplaceAllString(text, "$1")
+}
+
+// escapeMarkdownLines escapes the lines of a paragraph or quote that would otherwise start another block
+func escapeMarkdownLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case markdownBlockStartPattern.MatchString(line):
+			lines[i] = `\` + line
+		case markdownOrderedStart.MatchString(line):
+			lines[i] = markdownOrderedStart.ReplaceAllString(line, `$1\$2`)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unescapeMarkdownLines joins the lines of a paragraph or quote and removes the escapes escapeMarkdownLines added
+func unescapeMarkdownLines(lines []string) string {
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, `\`) && (markdownBlockStartPattern.MatchString(line[1:]) || strings.TrimSpace(line[1:]) == ""):
+			lines[i] = line[1:]
+		case markdownEscapedOrdered.MatchString(line):
+			lines[i] = markdownEscapedOrdered.ReplaceAllString(line, "$1$2")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
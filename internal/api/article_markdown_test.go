@@ -0,0 +1,175 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMarkdownRoundTripArticle(t *testing.T) {
+	tests := []struct {
+		name   string
+		blocks []ArticleBlock
+	}{
+		{
+			name: "Every block type",
+			blocks: []ArticleBlock{
+				{Type: BlockTypeHeading, Level: 2, Text: "Installing Go"},
+				{Type: BlockTypeParagraph, Text: "Download the installer\nand run it."},
+				{Type: BlockTypeImage, URL: "/api/uploads/go.png", Alt: "The Go gopher", Caption: "Our mascot"},
+				{Type: BlockTypeQuote, Text: "Clear is better than clever.", Cite: "Rob Pike"},
+				{Type: BlockTypeCode, Language: "go", Code: "func main() {\n\tfmt.Println(\"hello\")\n}"},
+				{Type: BlockTypeList, Items: []string{"Install", "Write code", "Ship it"}},
+				{Type: BlockTypeList, Ordered: true, Items: []string{"First step", "Second step\nwith a second line"}},
+				{Type: BlockTypeEmbed, URL: "https://www.youtube.com/watch?v=abc", Caption: "Talk"},
+				{Type: BlockTypeHeading, Level: 6, Text: "Small print"},
+			},
+		},
+		{
+			name: "Text that looks like Markdown",
+			blocks: []ArticleBlock{
+				{Type: BlockTypeParagraph, Text: "# not a heading\n- not a list\n1. not numbered\n> not a quote"},
+				{Type: BlockTypeParagraph, Text: "```\n![not](an-image)\n@[not](an-embed)\n\\ backslash"},
+				{Type: BlockTypeQuote, Text: "— not a cite\nsecond line", Cite: "Someone"},
+				{Type: BlockTypeQuote, Text: "first paragraph\n\nsecond paragraph"},
+				{Type: BlockTypeImage, URL: "https://example.com/a.png", Alt: "brackets ] and \\ slashes", Caption: `"quoted"`},
+			},
+		},
+		{
+			name: "Blank lines inside blocks",
+			blocks: []ArticleBlock{
+				{Type: BlockTypeParagraph, Text: "first part\n\nsecond part\n  \nthird part"},
+				{Type: BlockTypeParagraph, Text: "\\\nbackslash line"},
+				{Type: BlockTypeList, Items: []string{"item\n\nmore of the item", "next\n\\ backslash\n\\"}},
+				{Type: BlockTypeList, Ordered: true, Items: []string{"first\n  \nsecond", "third"}},
+			},
+		},
+		{
+			name: "Code containing fences",
+			blocks: []ArticleBlock{
+				{Type: BlockTypeCode, Language: "markdown", Code: "```go\nfmt.Println()\n```"},
+				{Type: BlockTypeCode, Code: "trailing newline\n"},
+			},
+		},
+	}
+
+	publishAt := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := markdownArticle{
+				Title:      "Getting started: with Go",
+				CategoryID: uuid.MustParse("6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c"),
+				ImageUrl:   "/api/uploads/cover.png",
+				Status:     "scheduled",
+				PublishAt:  &publishAt,
+				Tags:       []string{"go", "beginners"},
+				Body:       ArticleBody{Version: ArticleBodyVersion, Blocks: tt.blocks},
+			}
+			source := renderMarkdownArticle(want)
+			got, err := parseMarkdownArticle(source)
+			if err != nil {
+				t.Fatalf("parseMarkdownArticle() error = %v\n%s", err, source)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the article\n got: %+v\nwant: %+v\nmarkdown:\n%s", got, want, source)
+			}
+		})
+	}
+}
+
+func TestMarkdownRoundTripSource(t *testing.T) {
+	source := `---
+title: Getting started with Go
+category_id: 6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c
+tags: go, beginners
+---
+
+## Installing Go
+
+Download the installer from the Go website, it's "free" & works where a < b.
+
+> Clear is better than clever.
+> — Rob Pike
+
+1. Install
+2. Write code
+
+` + "```go\nfmt.Println(\"hello\")\n```" + `
+`
+	article, err := parseMarkdownArticle(source)
+	if err != nil {
+		t.Fatalf("parseMarkdownArticle() error = %v", err)
+	}
+	// render the body as it is saved
+	article.Body, err = validateArticle(article.Body)
+	if err != nil {
+		t.Fatalf("validateArticle() error = %v", err)
+	}
+	if got := renderMarkdownArticle(article); got != source {
+		t.Errorf("renderMarkdownArticle() =\n%s\nwant\n%s", got, source)
+	}
+}
+
+func TestParseMarkdownArticle(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantTitle string
+		wantBlock int
+		wantErr   bool
+	}{
+		{
+			name:      "Title from front matter",
+			source:    "---\ntitle: Hello\n---\n\n# Heading\n\nText",
+			wantTitle: "Hello",
+			wantBlock: 2,
+		},
+		{
+			name:      "Title from leading heading",
+			source:    "# Hello\r\n\r\nText\r\n",
+			wantTitle: "Hello",
+			wantBlock: 1,
+		},
+		{
+			name:    "Missing title",
+			source:  "## Not a title\n\nText",
+			wantErr: true,
+		},
+		{
+			name:    "Unclosed front matter",
+			source:  "---\ntitle: Hello\n\nText",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown front matter key",
+			source:  "---\ntitle: Hello\nauthor: me\n---\n\nText",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid category",
+			source:  "---\ntitle: Hello\ncategory_id: news\n---\n\nText",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, err := parseMarkdownArticle(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMarkdownArticle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if article.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", article.Title, tt.wantTitle)
+			}
+			if len(article.Body.Blocks) != tt.wantBlock {
+				t.Errorf("blocks = %d, want %d", len(article.Body.Blocks), tt.wantBlock)
+			}
+		})
+	}
+}
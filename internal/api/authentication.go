@@ -2,39 +2,202 @@ package api
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
 )
 
+// refreshTokenCookie is the name of the cookie browser clients keep their refresh token in
+const refreshTokenCookie = "refresh-token"
+
 func (cfg *APIConfig) middlewareAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Try to get the token from the "Authorization" header
-		authHeader := r.Header.Get("Authorization")
-		var token string
-
-		if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-			token = strings.TrimPrefix(authHeader, "Bearer ")
-		} else {
-			// If no Authorization header, try to get the token from the "auth-token" cookie
-			cookie, err := r.Cookie("auth-token")
-			if err != nil {
-				http.Error(w, "Unauthorized: missing token", http.StatusUnauthorized)
-				return
-			}
-			token = cookie.Value
+		token, err := getRequestToken(r)
+		if err != nil {
+			http.Error(w, "Unauthorized: missing token", http.StatusUnauthorized)
+			return
 		}
 
 		// Validate the token
-		userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+		accessToken, err := auth.ParseJWT(token, cfg.jwtSecret)
 		if err != nil {
 			http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
 			return
 		}
+		active, err := cfg.accessTokenActive(r.Context(), accessToken)
+		if err != nil {
+			http.Error(w, "Failed to check token", http.StatusInternalServerError)
+			return
+		}
+		if !active {
+			http.Error(w, "Unauthorized: logged out token", http.StatusUnauthorized)
+			return
+		}
 
-		// Add the user ID to the request context
-		ctx := context.WithValue(r.Context(), "userID", userID)
+		// Add the user and session ID to the request context
+		ctx := context.WithValue(r.Context(), "userID", accessToken.UserID)
+		ctx = context.WithValue(ctx, "sessionID", accessToken.SessionID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// middlewareAdmin only lets authenticated users with the admin flag through
+func (cfg *APIConfig) middlewareAdmin(next http.Handler) http.Handler {
+	return cfg.middlewareAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value("userID").(uuid.UUID) // set by middlewareAuth
+
+		// Look the user up on every request, so revoking the flag takes effect immediately
+		user, err := cfg.db.GetUserByID(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Unauthorized: unknown user", http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "Failed to retrieve user", http.StatusInternalServerError)
+			return
+		}
+		if !user.IsAdmin {
+			http.Error(w, "Forbidden: admin access required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// middlewareVerifiedEmail only lets authenticated users through once they confirmed their email address,
+// when the API is configured to require it
+func (cfg *APIConfig) middlewareVerifiedEmail(next http.Handler) http.Handler {
+	return cfg.middlewareAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.requireVerifiedEmail {
+			next.ServeHTTP(w, r)
+			return
+		}
+		userID := r.Context().Value("userID").(uuid.UUID) // set by middlewareAuth
+
+		user, err := cfg.db.GetUserByID(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Unauthorized: unknown user", http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "Failed to retrieve user", http.StatusInternalServerError)
+			return
+		}
+		if !user.EmailVerifiedAt.Valid {
+			http.Error(w, "Forbidden: verify your email address first", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// middlewareOptionalAuth adds the user ID to the request context when a valid token is present,
+// but lets anonymous requests through so public endpoints can tailor their response to the viewer.
+// The session is not looked up, public reads stay a single query: a token of an ended session only shows its
+// user their own unpublished articles until it expires, everything that changes data goes through middlewareAuth.
+func (cfg *APIConfig) middlewareOptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := getRequestToken(r)
+		if err == nil {
+			if accessToken, err := auth.ParseJWT(token, cfg.jwtSecret); err == nil {
+				r = r.WithContext(context.WithValue(r.Context(), "userID", accessToken.UserID))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accessTokenActive reports whether an access token was not logged out and the session it was issued for still has
+// a refresh token that is neither revoked nor expired, so ending a session, on its own or by resetting the password,
+// also ends its access tokens. Tokens that do not name a session cannot be ended and are not accepted.
+func (cfg *APIConfig) accessTokenActive(ctx context.Context, accessToken auth.AccessToken) (bool, error) {
+	if accessToken.SessionID == uuid.Nil {
+		return false, nil
+	}
+	return cfg.db.IsAccessTokenActive(ctx, database.IsAccessTokenActiveParams{
+		Jti:       accessToken.ID,
+		SessionID: accessToken.SessionID,
+	})
+}
+
+// getRequestToken reads the access token from the Authorization header, falling back to the auth-token cookie
+func getRequestToken(r *http.Request) (string, error) {
+	// Try to get the token from the "Authorization" header
+	authHeader := r.Header.Get("Authorization")
+	if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimPrefix(authHeader, "Bearer "), nil
+	}
+
+	// If no Authorization header, try to get the token from the "auth-token" cookie
+	cookie, err := r.Cookie("auth-token")
+	if err != nil {
+		return "", errors.New("missing token")
+	}
+	return cookie.Value, nil
+}
+
+// getRefreshToken reads the refresh token from the Authorization header, falling back to the refresh-token cookie
+// browser clients hold it in. fromCookie tells which one it came from, so the new token can be sent back the same way.
+func getRefreshToken(r *http.Request) (token string, fromCookie bool, err error) {
+	token, err = auth.GetBearerToken(r.Header)
+	if err == nil {
+		return token, false, nil
+	}
+	cookie, cookieErr := r.Cookie(refreshTokenCookie)
+	if cookieErr != nil || cookie.Value == "" {
+		return "", false, err
+	}
+	return cookie.Value, true, nil
+}
+
+// setSessionCookies hands browser clients their tokens as HttpOnly cookies, the refresh token is only sent to the API
+func setSessionCookies(w http.ResponseWriter, accessToken, refreshToken string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "auth-token",
+		Value:    accessToken,
+		HttpOnly: true,
+		Path:     "/",
+		Expires:  time.Now().Add(time.Hour), // Set the expiration time for the cookie to match the token expiration
+		Secure:   true,                      // set to true if using HTTPS (during production).
+		SameSite: http.SameSiteNoneMode,     // allow cross-site usage
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    refreshToken,
+		HttpOnly: true,
+		Path:     "/api",
+		Expires:  time.Now().Add(refreshTokenDuration),
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+// clearSessionCookies removes the token cookies of browser clients
+func clearSessionCookies(w http.ResponseWriter) {
+	for _, cookie := range []struct{ name, path string }{{"auth-token", "/"}, {refreshTokenCookie, "/api"}} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     cookie.name,
+			Value:    "",
+			Path:     cookie.path,
+			Expires:  time.Unix(0, 0), // expire immediately
+			HttpOnly: true,
+			Secure:   true, // Set to true in production with HTTPS
+			SameSite: http.SameSiteNoneMode,
+		})
+	}
+}
+
+// viewerID returns the ID of the authenticated user, or uuid.Nil for anonymous requests
+func viewerID(r *http.Request) uuid.UUID {
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+	return userID
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/google/uuid"
+)
+
+func TestMiddlewareVerifiedEmail(t *testing.T) {
+	token, _ := auth.MakeJWT(uuid.New(), uuid.New(), "secret", time.Hour)
+
+	for _, tt := range []struct {
+		name    string
+		require bool
+		want    int
+	}{
+		{name: "Not required", require: false, want: http.StatusNoContent},
+		{name: "Required and unverified", require: true, want: http.StatusForbidden}, // canned users have no email_verified_at
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := newCountingConfig(0)
+			cfg.SetRequireVerifiedEmail(tt.require)
+			handler := cfg.middlewareVerifiedEmail(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/articles", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareAuthRejectsDeniedToken(t *testing.T) {
+	cfg, connector := newCountingConfig(0)
+	sessionID := uuid.New()
+	denied, _ := auth.MakeJWT(uuid.New(), sessionID, "secret", time.Hour)
+	sameSession, _ := auth.MakeJWT(uuid.New(), sessionID, "secret", time.Hour)
+	parsed, _ := auth.ParseJWT(denied, "secret")
+	connector.deniedTokens.Store(parsed.ID, true)
+
+	handler := cfg.middlewareAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for token, want := range map[string]int{denied: http.StatusUnauthorized, sameSession: http.StatusNoContent} {
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("status = %d, want %d", rec.Code, want)
+		}
+	}
+	if got := connector.queries.Load(); got != 2 {
+		t.Errorf("queries = %d, want one per request", got)
+	}
+}
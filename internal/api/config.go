@@ -2,25 +2,57 @@ package api
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/GitIBB/pursuit/internal/mail"
 )
 
 type APIConfig struct { // struct to hold configuration for the API
 	fileserverHits atomic.Int32      // counter for file server hits
+	dbCon          *sql.DB           // database connection pool, for queries that have to run in one transaction
 	db             *database.Queries // database connection
 	platform       string            // platform name
 	jwtSecret      string            // JWT secret for signing tokens
+	baseURL        string            // public URL of the site used in mails, feeds and sitemaps
+	sitemaps       *responseCache    // generated sitemaps, rebuilt once they are older than sitemapCacheTTL
+	sitemapCount   sitemapCounter    // number of URLs in the sitemaps, counted again once older than sitemapCacheTTL
+	mailer         mail.Sender       // delivers password reset and verification mails, logs them unless SetMailer is called, which is for development only
+	background     sync.WaitGroup    // work that carries on after its response was sent, like sending mail
+
+	requireVerifiedEmail bool // users have to confirm their email address before they can create articles
 }
 
-func NewAPIConfig(db *database.Queries, platform, jwtSecret string) *APIConfig {
+func NewAPIConfig(dbCon *sql.DB, platform, jwtSecret string) *APIConfig {
 	return &APIConfig{
 		fileserverHits: atomic.Int32{},
-		db:             db,
+		dbCon:          dbCon,
+		db:             database.New(dbCon),
 		platform:       platform,
 		jwtSecret:      jwtSecret,
+		sitemaps:       newResponseCache(sitemapCacheTTL),
+		mailer:         mail.NewLogSender(os.Stderr),
+	}
+}
+
+// inTx runs fn with queries bound to a transaction, which is committed when fn succeeds and rolled back otherwise
+func (cfg *APIConfig) inTx(ctx context.Context, fn func(q *database.Queries) error) error {
+	tx, err := cfg.dbCon.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // does nothing once the transaction is committed
+
+	if err := fn(cfg.db.WithTx(tx)); err != nil {
+		return err
 	}
+	return tx.Commit()
 }
 
 // Controlled Access Methods
@@ -43,3 +75,40 @@ func (cfg *APIConfig) GetPlatform() string {
 func (cfg *APIConfig) GetJWTSecret() string {
 	return cfg.jwtSecret
 }
+
+func (cfg *APIConfig) SetBaseURL(baseURL string) {
+	cfg.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+func (cfg *APIConfig) SetMailer(mailer mail.Sender) {
+	cfg.mailer = mailer
+}
+
+func (cfg *APIConfig) SetRequireVerifiedEmail(require bool) {
+	cfg.requireVerifiedEmail = require
+}
+
+// mailURL returns the link to path on the public site for use in a mail. It is never built from the request, whose
+// Host header is picked by the client and would let anyone send working reset links to their own site.
+func (cfg *APIConfig) mailURL(path string, query url.Values) (string, error) {
+	if cfg.baseURL == "" {
+		return "", errors.New("BASE_URL is not set, links in mails need the public URL of the site")
+	}
+	return cfg.baseURL + path + "?" + query.Encode(), nil
+}
+
+// siteURL returns the public URL of the site without a trailing slash, as configured and never from the request,
+// so cached documents cannot pick up a Host header chosen by the client
+func (cfg *APIConfig) siteURL() string {
+	return cfg.baseURL
+}
+
+// articleURL returns the public URL of an article, which serves its rendered body
+func (cfg *APIConfig) articleURL(slug string) string {
+	return cfg.siteURL() + "/api/articles/by-slug/" + url.PathEscape(slug) + "?format=html"
+}
+
+// categoryURL returns the public URL of a category, which lists its articles
+func (cfg *APIConfig) categoryURL(slug string) string {
+	return cfg.siteURL() + "/api/categories/" + url.PathEscape(slug) + "/articles"
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes that handlers turn into client errors
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+// isPQError reports whether err is a Postgres error with the given code
+func isPQError(err error, code string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == code
+}
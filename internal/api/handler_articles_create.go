@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -11,31 +13,43 @@ import (
 	"github.com/google/uuid"
 )
 
+const maxMarkdownArticleSize = 1 << 20 // maximum size in bytes of an article imported as Markdown
+
 type Article struct { // struct to hold article data
-	ID        uuid.UUID   `json:"id"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
-	UserID    uuid.UUID   `json:"user_id"`
-	Category  string      `json:"category"` // Category of the article, can be retrieved from the database category table
-	Title     string      `json:"title"`
-	Body      ArticleBody `json:"body"`
-	ImageUrl  string      `json:"image_url"`
-	Username  string      `json:"username"` // Username of the author, can be retrieved from the database user table
+	ID           uuid.UUID      `json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	UserID       uuid.UUID      `json:"user_id"`
+	Category     string         `json:"category"` // Category of the article, can be retrieved from the database category table
+	Title        string         `json:"title"`
+	Body         ArticleBody    `json:"body"`
+	ImageUrl     string         `json:"image_url"`
+	Username     string         `json:"username"` // Username of the author, can be retrieved from the database user table
+	Status       string         `json:"status"`
+	PublishedAt  *time.Time     `json:"published_at"` // nil until the article is published for the first time
+	PublishAt    *time.Time     `json:"publish_at"`   // time a scheduled article goes live
+	ViewCount    int64          `json:"view_count"`
+	Slug         string         `json:"slug"`                    // URL slug derived from the title, see GET /api/articles/by-slug/{slug}
+	Tags         []string       `json:"tags"`                    // slugs of the tags on the article
+	Series       *ArticleSeries `json:"series"`                  // nil when the article is not part of a series
+	RenderedHTML string         `json:"rendered_html,omitempty"` // sanitized HTML of the body, only on the article detail endpoints
 }
 
-type ArticleBody struct { // struct to hold article body data
-	Headers map[string]string          `json:"headers"`
-	Content map[string]json.RawMessage `json:"content"`
-	Images  map[string]string          `json:"images"`
+type ArticleBody struct { // struct to hold article body data, see article_body.go for the blocks and the legacy shape
+	Version int            `json:"version"`
+	Blocks  []ArticleBlock `json:"blocks"` // blocks in reading order
 }
 
-// Handler function to create a new article
+// Handler function to create a new article from JSON, or from Markdown when the request is sent as text/markdown
 func (cfg *APIConfig) handlerArticlesCreate(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Title       string      `json:"title"`
 		ArticleBody ArticleBody `json:"article_body"`
 		ImageUrl    string      `json:"image_url"`
 		CategoryID  uuid.UUID   `json:"category_id"`
+		Status      string      `json:"status"`     // draft, scheduled or published, defaults to published (or scheduled when publish_at is set)
+		PublishAt   *time.Time  `json:"publish_at"` // time the article should go live, must be in the future
+		Tags        []string    `json:"tags"`       // tag names, stored as slugs
 	}
 
 	// Retrieve the user ID from the context
@@ -45,42 +59,131 @@ func (cfg *APIConfig) handlerArticlesCreate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
-	params := parameters{}             // Create a new instance of the parameters struct
-	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to decode request parameters", err)
-		return
+	params := parameters{} // Create a new instance of the parameters struct
+	if requestMediaType(r) == "text/markdown" {
+		// Markdown articles carry the other parameters in their front matter
+		source, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMarkdownArticleSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Markdown article is too large", err)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+		article, err := parseMarkdownArticle(string(source))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Failed to parse Markdown article", err)
+			return
+		}
+		params = parameters{
+			Title:       article.Title,
+			ArticleBody: article.Body,
+			ImageUrl:    article.ImageUrl,
+			CategoryID:  article.CategoryID,
+			Status:      article.Status,
+			PublishAt:   article.PublishAt,
+			Tags:        article.Tags,
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+		err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to decode request parameters", err)
+			return
+		}
 	}
 
 	cleanedBody, err := validateArticle(params.ArticleBody) // Validate the article body
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
+		return
 	}
 
-	bodyJSON, err := json.Marshal(cleanedBody) // Marshal the cleaned body to JSON
+	tagNames, tagSlugs, err := normalizeTags(params.Tags) // Validate the tags
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to marshal article body", err)
+		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
 		return
 	}
 
-	// Save the article to the database
-	article, err := cfg.db.CreateArticle(r.Context(), database.CreateArticleParams{
-		UserID:     userID,            // save the user ID from the context
-		CategoryID: params.CategoryID, // save the category ID
-		Title:      params.Title,      // save the title
-		Body:       bodyJSON,          // save the marshaled JSON body
-		ImageUrl: sql.NullString{
-			String: params.ImageUrl,       // save the image URL
-			Valid:  params.ImageUrl != "", // Set Valid to true if imageURL is not empty
-		},
-	})
+	// New articles start out as drafts, are scheduled for later or are published right away
+	if params.Status == "" {
+		params.Status = ArticleStatusPublished
+		if params.PublishAt != nil {
+			params.Status = ArticleStatusScheduled
+		}
+	}
+	publishAt := sql.NullTime{}
+	switch params.Status {
+	case ArticleStatusDraft, ArticleStatusPublished:
+		if params.PublishAt != nil {
+			respondWithError(w, http.StatusBadRequest, "publish_at can only be used with the scheduled status", nil)
+			return
+		}
+	case ArticleStatusScheduled:
+		if params.PublishAt == nil || !params.PublishAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "Scheduling an article requires a publish_at in the future", nil)
+			return
+		}
+		publishAt = sql.NullTime{Time: params.PublishAt.UTC(), Valid: true}
+	default:
+		respondWithError(w, http.StatusBadRequest, "Status must be draft, scheduled or published", nil)
+		return
+	}
 
+	bodyJSON, err := json.Marshal(cleanedBody) // Marshal the cleaned body to JSON
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to create article", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to marshal article body", err)
 		return
 	}
 
+	// Save the article and its tags in one transaction under a slug derived from its title,
+	// the slug is picked again when another article claimed it in the meantime
+	var article database.Article
+	for attempt := 1; ; attempt++ {
+		slug, err := cfg.nextArticleSlug(r.Context(), params.Title, uuid.Nil)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to generate article slug", err)
+			return
+		}
+		err = cfg.inTx(r.Context(), func(q *database.Queries) error {
+			article, err = q.CreateArticle(r.Context(), database.CreateArticleParams{
+				UserID:     userID,            // save the user ID from the context
+				CategoryID: params.CategoryID, // save the category ID
+				Title:      params.Title,      // save the title
+				Body:       bodyJSON,          // save the marshaled JSON body
+				ImageUrl: sql.NullString{
+					String: params.ImageUrl,       // save the image URL
+					Valid:  params.ImageUrl != "", // Set Valid to true if imageURL is not empty
+				},
+				Status:    params.Status, // save the status, published articles get their published_at set by the query
+				PublishAt: publishAt,     // save the scheduled publish time
+				Slug:      slug,          // save the slug
+			})
+			if err != nil || len(tagSlugs) == 0 {
+				return err
+			}
+			// Attach the tags, creating the ones that do not exist yet
+			return q.AddArticleTags(r.Context(), database.AddArticleTagsParams{
+				Names:     tagNames,
+				Slugs:     tagSlugs,
+				ArticleID: article.ID,
+			})
+		})
+		if err != nil && isPQError(err, pqUniqueViolation) && attempt < maxArticleSlugAttempts {
+			continue
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to create article", err)
+			return
+		}
+		break
+	}
+	if len(tagSlugs) == 0 {
+		tagSlugs = []string{}
+	}
+
 	// retrieve username from the database
 	user, err := cfg.db.GetUserByID(r.Context(), article.UserID)
 	if err != nil {
@@ -89,26 +192,37 @@ func (cfg *APIConfig) handlerArticlesCreate(w http.ResponseWriter, r *http.Reque
 	}
 
 	respondWithJSON(w, http.StatusCreated, Article{ // Create a new response instance containing the article data
-		ID:        article.ID,
-		CreatedAt: article.CreatedAt,
-		UpdatedAt: article.UpdatedAt,
-		UserID:    article.UserID,
-		Title:     params.Title,
-		Body:      cleanedBody,
-		ImageUrl:  params.ImageUrl,
-		Username:  user.Username, // Username is retrieved from the database
+		ID:          article.ID,
+		CreatedAt:   article.CreatedAt,
+		UpdatedAt:   article.UpdatedAt,
+		UserID:      article.UserID,
+		Title:       params.Title,
+		Body:        cleanedBody,
+		ImageUrl:    params.ImageUrl,
+		Username:    user.Username, // Username is retrieved from the database
+		Status:      article.Status,
+		PublishedAt: nullTimePtr(article.PublishedAt),
+		PublishAt:   nullTimePtr(article.PublishAt),
+		ViewCount:   article.ViewCount,
+		Slug:        article.Slug,
+		Tags:        tagSlugs,
 	})
 }
 
 func validateArticle(body ArticleBody) (ArticleBody, error) { // Function to validate the article body
-	if body.Content == nil {
-		return body, errors.New("content is required")
+	if len(body.Blocks) == 0 {
+		return body, errors.New("body needs at least one block")
+	}
+	if len(body.Blocks) > maxArticleBlocks {
+		return body, fmt.Errorf("body can have at most %d blocks", maxArticleBlocks)
 	}
-	requiredSections := []string{"introduction", "mainBody", "conclusion"}
-	for _, section := range requiredSections {
-		if _, ok := body.Content[section]; !ok {
-			return body, errors.New(section + " is required in content")
+	cleaned := ArticleBody{Version: ArticleBodyVersion, Blocks: make([]ArticleBlock, 0, len(body.Blocks))}
+	for i, block := range body.Blocks {
+		cleanedBlock, err := validateBlock(block)
+		if err != nil {
+			return body, fmt.Errorf("block %d: %w", i+1, err)
 		}
+		cleaned.Blocks = append(cleaned.Blocks, cleanedBlock)
 	}
-	return body, nil
+	return cleaned, nil
 }
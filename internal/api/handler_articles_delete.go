@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/GitIBB/pursuit/internal/database"
 	"github.com/google/uuid"
 )
 
@@ -24,7 +25,10 @@ func (cfg *APIConfig) handlerArticlesDelete(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Retrieve the article from the database using the article ID
-	dbArticle, err := cfg.db.GetArticle(r.Context(), articleID)
+	dbArticle, err := cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       articleID,
+		ViewerID: userID,
+	})
 	if err != nil {
 		respondWithError(w, http.StatusNotFound, "Article not found", err)
 		return
@@ -1,9 +1,12 @@
 package api
 
 import (
-	"encoding/json"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
+	"reflect"
+	"slices"
 
 	"github.com/GitIBB/pursuit/internal/database"
 	"github.com/google/uuid"
@@ -17,165 +20,393 @@ func (cfg *APIConfig) handlerArticlesGet(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Use the GetArticle function to retrieve the article by its ID
-	dbArticle, err := cfg.db.GetArticle(r.Context(), articleID)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
-		return
+	if !cfg.respondWithArticleView(w, r, database.ViewArticleParams{ID: uuid.NullUUID{UUID: articleID, Valid: true}}) {
+		respondWithError(w, http.StatusNotFound, "Article not found", nil)
 	}
+}
 
-	// Fetch the username using GetUserByID
-	user, err := cfg.db.GetUserByID(r.Context(), dbArticle.UserID)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user", err)
-		return
+// respondWithArticleView looks up the article by the ID or slug in params, counts the view and responds with it
+// as JSON, as Markdown when the Accept header or ?format=markdown asks for it, or its body as HTML for ?format=html.
+// It returns false without responding when there is no article the viewer can see.
+func (cfg *APIConfig) respondWithArticleView(w http.ResponseWriter, r *http.Request, params database.ViewArticleParams) bool {
+	format := r.URL.Query().Get("format") // Get the format query parameter from the URL
+	if format == "" && negotiateContentType(r, "application/json", "text/markdown") == "text/markdown" {
+		format = "markdown"
+	}
+	if format != "" && format != "json" && format != "markdown" && format != "html" {
+		respondWithError(w, http.StatusBadRequest, "Format must be json, markdown or html", nil)
+		return true
 	}
 
-	// After fetching dbArticle
-	category, err := cfg.db.GetCategoryByID(r.Context(), dbArticle.CategoryID)
+	// Retrieve the article together with its author and category and count the view in a single query,
+	// drafts and scheduled articles are only visible to their author, who does not add to the view count
+	viewer := viewerID(r)
+	params.ViewerID = viewer
+	dbArticle, err := cfg.db.ViewArticle(r.Context(), params)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve category", err)
-		return
+		if errors.Is(err, sql.ErrNoRows) {
+			return false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return true
+	}
+	if dbArticle.UserID != viewer {
+		dbArticle.ViewCount++ // the row was read before the view was counted
 	}
 
-	var body ArticleBody                        // Initialize an empty ArticleBody struct
-	err = json.Unmarshal(dbArticle.Body, &body) // Unmarshal the article body from JSON into the struct
+	// The view rows carry the same article columns as GetArticle, followed by the neighbours in the series
+	article, err := newArticle(articleRowOf(dbArticle))
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
-		return
+		return true
 	}
 
-	// Handle sql.NullString for ImageUrl
-	imageUrl := ""
-	if dbArticle.ImageUrl.Valid {
-		imageUrl = dbArticle.ImageUrl.String
+	// Serve the body as HTML or the article as Markdown when the client asked for it
+	w.Header().Set("Vary", "Accept")
+	switch format {
+	case "html":
+		respondWithText(w, http.StatusOK, "text/html", renderArticleHTML(article.Body))
+		return true
+	case "markdown":
+		respondWithText(w, http.StatusOK, "text/markdown", renderMarkdownArticle(markdownArticle{
+			Title:      article.Title,
+			CategoryID: dbArticle.CategoryID,
+			ImageUrl:   article.ImageUrl,
+			Status:     article.Status,
+			PublishAt:  article.PublishAt,
+			Tags:       article.Tags,
+			Body:       article.Body,
+		}))
+		return true
 	}
+	article.RenderedHTML = renderArticleHTML(article.Body)
 
-	respondWithJSON(w, http.StatusOK, Article{
-		ID:        dbArticle.ID,
-		CreatedAt: dbArticle.CreatedAt,
-		UpdatedAt: dbArticle.UpdatedAt,
-		UserID:    dbArticle.UserID,
-		Title:     dbArticle.Title,
-		Body:      body,
-		ImageUrl:  imageUrl,
-		Username:  user.Username,
-		Category:  category.Name,
-	})
+	// Link to the neighbouring articles the viewer can see when the article is part of a series,
+	// the view query looks them up along with the article
+	if article.Series != nil {
+		if dbArticle.PreviousID.Valid {
+			article.Series.Previous = &SeriesArticle{
+				ID:       dbArticle.PreviousID.UUID,
+				Title:    dbArticle.PreviousTitle.String,
+				Status:   dbArticle.PreviousStatus.String,
+				Position: dbArticle.PreviousPosition.Int32,
+			}
+		}
+		if dbArticle.NextID.Valid {
+			article.Series.Next = &SeriesArticle{
+				ID:       dbArticle.NextID.UUID,
+				Title:    dbArticle.NextTitle.String,
+				Status:   dbArticle.NextStatus.String,
+				Position: dbArticle.NextPosition.Int32,
+			}
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, article)
+	return true
 }
 
 func (cfg *APIConfig) handlerArticlesRetrieve(w http.ResponseWriter, r *http.Request) { // Handler function to retrieve all articles with pagination
-	// parse query parameters for pagination
-	page := r.URL.Query().Get("page")   // Get the page query parameter from the URL
-	limit := r.URL.Query().Get("limit") // Get the limit query parameter from the URL
-
-	// Default values for pagination
-	pageNum := 1
-	limitNum := 10
-
-	// Convert query parameters to integers
-	if page != "" {
-		if p, err := strconv.Atoi(page); err == nil && p > 0 {
-			pageNum = p // Set pageNum to the parsed value if it's a valid positive integer
-		}
-	}
-	if limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			limitNum = l // <-- Use the parsed value!
-		}
+	filters, err := parseArticleFilters(r) // parse query parameters for filtering
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
 	}
 
+	cfg.retrieveArticles(w, r, filters)
+}
+
+// retrieveArticles responds with the articles matching the filters, sorted by the sort query parameter
+// and paginated by page number or, when a cursor query parameter is given, by cursor
+func (cfg *APIConfig) retrieveArticles(w http.ResponseWriter, r *http.Request, filters database.CountArticlesParams) {
+	pageNum, limitNum := parsePagination(r) // parse query parameters for pagination
+
 	//calculate the offset
 	offset := (pageNum - 1) * limitNum // Calculate the offset for pagination
 
-	// Fetch the total number of articles
-	totalRecords, err := cfg.db.GetTotalArticlesCount(r.Context())
+	sort := r.URL.Query().Get("sort") // Get the sort query parameter from the URL
+	if sort == "" {
+		sort = "oldest"
+	}
+	if !validArticleSorts[sort] {
+		respondWithError(w, http.StatusBadRequest, "Sort must be one of newest, oldest, title or most_viewed", nil)
+		return
+	}
+
+	// Cursor mode walks the listing from an opaque cursor instead of a page number, an empty cursor starts at the beginning
+	if r.URL.Query().Has("cursor") {
+		cfg.retrieveArticlesByCursor(w, r, filters, sort)
+		return
+	}
+
+	// Fetch the total number of articles matching the filters
+	totalRecords, err := cfg.db.CountArticles(r.Context(), filters)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve total articles count", err)
 		return
 	}
 
 	// Calculate total pages
-	totalPages := (int(totalRecords) + limitNum - 1) / limitNum // Round up
+	totalPages := countPages(totalRecords, limitNum) // Round up
 
 	// Create getArticlesParams struct to pass to GetArticles function
 	params := database.GetArticlesParams{
-		Limit:  int32(limitNum),
-		Offset: int32(offset),
+		ViewerID:     filters.ViewerID,
+		UserID:       filters.UserID,
+		CategoryID:   filters.CategoryID,
+		CategoryName: filters.CategoryName,
+		CreatedFrom:  filters.CreatedFrom,
+		CreatedTo:    filters.CreatedTo,
+		Status:       filters.Status,
+		Sort:         sort,
+		Limit:        int32(limitNum),
+		Offset:       int32(offset),
 	}
 
-	dbArticles, err := cfg.db.GetArticles(r.Context(), params) // Retrieve all articles from the database
+	dbArticles, err := cfg.db.GetArticles(r.Context(), params) // Retrieve the matching articles from the database
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve articles", err)
 		return
 	}
 
-	authorID := uuid.Nil                           // Initialize authorID to uuid.Nil
-	authorIDString := r.URL.Query().Get("user_id") // Get the author_id query parameter from the URL
-	if authorIDString != "" {                      // If author_id is provided
-		authorID, err = uuid.Parse(authorIDString) // Parse the author_id string to a UUID
+	articles := []Article{}                // Initialize an empty slice of Article
+	for _, dbArticle := range dbArticles { // Iterate over the retrieved articles, author and category are part of each row
+		article, err := newArticle(database.GetArticleRow(dbArticle))
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid author ID", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
 			return
 		}
+		articles = append(articles, article)
 	}
 
-	articles := []Article{}                // Initialize an empty slice of Article
-	for _, dbArticle := range dbArticles { // Iterate over the retrieved articles
-		if authorID != uuid.Nil && dbArticle.UserID != authorID { // Check if the article's author ID matches the provided author ID
-			continue // If not, skip this article
-		}
+	// Create the response with metadata
+	type Metadata struct {
+		CurrentPage  int `json:"current_page"`
+		TotalPages   int `json:"total_pages"`
+		TotalRecords int `json:"total_records"`
+	}
+	type Response struct {
+		Metadata Metadata  `json:"metadata"`
+		Articles []Article `json:"articles"`
+	}
 
-		// Unmarshal the article body from JSON into the ArticleBody struct
-		var body ArticleBody
-		err = json.Unmarshal(dbArticle.Body, &body)
+	respondWithJSON(w, http.StatusOK, Response{
+		Metadata: Metadata{
+			CurrentPage:  pageNum,
+			TotalPages:   totalPages,
+			TotalRecords: int(totalRecords),
+		},
+		Articles: articles,
+	})
+}
+
+// retrieveArticlesByCursor responds with one page of the articles matching the filters using keyset pagination,
+// which stays fast on large tables and does not repeat articles when new ones are created between page loads
+func (cfg *APIConfig) retrieveArticlesByCursor(w http.ResponseWriter, r *http.Request, filters database.CountArticlesParams, sort string) {
+	if sort != "newest" && sort != "oldest" {
+		respondWithError(w, http.StatusBadRequest, "Cursor pagination only supports the newest and oldest sorts", nil)
+		return
+	}
+	_, limitNum := parsePagination(r) // the page parameter is ignored in cursor mode
+
+	cursor := articleCursor{}
+	params := database.GetArticlesAfterParams{
+		ViewerID:     filters.ViewerID,
+		UserID:       filters.UserID,
+		CategoryID:   filters.CategoryID,
+		CategoryName: filters.CategoryName,
+		CreatedFrom:  filters.CreatedFrom,
+		CreatedTo:    filters.CreatedTo,
+		Status:       filters.Status,
+		Limit:        int32(limitNum + 1), // fetch one extra article to find out if there is another page
+	}
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		var err error
+		cursor, err = decodeCursor(value)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
 			return
 		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
 
-		// Fetch the category using GetCategoryByID
-		category, err := cfg.db.GetCategoryByID(r.Context(), dbArticle.CategoryID)
+	// Walking backwards through the oldest sort or forwards through the newest sort reads the table in descending order
+	var dbArticles []database.GetArticlesAfterRow
+	if (sort == "oldest") != cursor.Before {
+		rows, err := cfg.db.GetArticlesAfter(r.Context(), params)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve category", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve articles", err)
 			return
 		}
-
-		// Handle sql.NullString for ImageUrl
-		imageUrl := ""
-		if dbArticle.ImageUrl.Valid {
-			imageUrl = dbArticle.ImageUrl.String
+		dbArticles = rows
+	} else {
+		rows, err := cfg.db.GetArticlesBefore(r.Context(), database.GetArticlesBeforeParams(params))
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve articles", err)
+			return
 		}
+		for _, row := range rows {
+			dbArticles = append(dbArticles, database.GetArticlesAfterRow(row))
+		}
+	}
 
-		articles = append(articles, Article{
-			ID:        dbArticle.ID,
-			CreatedAt: dbArticle.CreatedAt,
-			UpdatedAt: dbArticle.UpdatedAt,
-			UserID:    dbArticle.UserID,
-			Title:     dbArticle.Title,
-			Body:      body,
-			ImageUrl:  imageUrl,
-			Username:  dbArticle.Username,
-			Category:  category.Name,
-		})
+	hasMore := len(dbArticles) > limitNum
+	if hasMore {
+		dbArticles = dbArticles[:limitNum]
 	}
-	// Create the response with metadata
+	if cursor.Before {
+		slices.Reverse(dbArticles) // pages are always returned in the requested sort order
+	}
+
+	articles := []Article{}
+	for _, dbArticle := range dbArticles {
+		article, err := newArticle(database.GetArticleRow(dbArticle))
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+			return
+		}
+		articles = append(articles, article)
+	}
+
+	// Create the response with cursors to the neighbouring pages
 	type Metadata struct {
-		CurrentPage  int `json:"current_page"`
-		TotalPages   int `json:"total_pages"`
-		TotalRecords int `json:"total_records"`
+		Limit      int     `json:"limit"`
+		NextCursor *string `json:"next_cursor"` // nil on the last page
+		PrevCursor *string `json:"prev_cursor"` // nil on the first page
 	}
 	type Response struct {
 		Metadata Metadata  `json:"metadata"`
 		Articles []Article `json:"articles"`
 	}
 
+	metadata := Metadata{Limit: limitNum}
+	// There are articles ahead of a backwards page by definition, and behind a forwards page whenever it started from a cursor
+	hasNext, hasPrev := hasMore, params.CursorID.Valid
+	if cursor.Before {
+		hasNext, hasPrev = true, hasMore
+	}
+	if len(dbArticles) > 0 {
+		first, last := dbArticles[0], dbArticles[len(dbArticles)-1]
+		if hasNext {
+			next := encodeCursor(articleCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+			metadata.NextCursor = &next
+		}
+		if hasPrev {
+			prev := encodeCursor(articleCursor{CreatedAt: first.CreatedAt, ID: first.ID, Before: true})
+			metadata.PrevCursor = &prev
+		}
+	}
+
 	respondWithJSON(w, http.StatusOK, Response{
-		Metadata: Metadata{
-			CurrentPage:  pageNum,
-			TotalPages:   totalPages,
-			TotalRecords: int(totalRecords),
-		},
+		Metadata: metadata,
 		Articles: articles,
 	})
 }
+
+// articleRowOf copies the columns GetArticle shares with a row of a query that selects more than it, like the
+// view and search queries, matched by field name. It panics when the row lacks one of them or has it with another
+// type, the read queries all select the same article columns and the tests read every one of them.
+func articleRowOf(row any) database.GetArticleRow {
+	var article database.GetArticleRow
+	dst := reflect.ValueOf(&article).Elem()
+	src := reflect.ValueOf(row)
+	for i := 0; i < dst.NumField(); i++ {
+		name := dst.Type().Field(i).Name
+		field := src.FieldByName(name)
+		if !field.IsValid() || field.Type() != dst.Field(i).Type() {
+			panic(fmt.Sprintf("%s has no %s column like database.GetArticleRow", src.Type(), name))
+		}
+		dst.Field(i).Set(field)
+	}
+	return article
+}
+
+// newArticle builds the Article response from a row of the article read queries, which all share the columns of GetArticle
+func newArticle(row database.GetArticleRow) (Article, error) {
+	body, err := decodeArticleBody(row.Body) // Unmarshal the article body from JSON into the struct
+	if err != nil {
+		return Article{}, err
+	}
+
+	return Article{
+		ID:          row.ID,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		UserID:      row.UserID,
+		Category:    row.CategoryName,
+		Title:       row.Title,
+		Body:        body,
+		ImageUrl:    row.ImageUrl.String, // empty when the article has no image
+		Username:    row.Username,
+		Status:      row.Status,
+		PublishedAt: nullTimePtr(row.PublishedAt),
+		PublishAt:   nullTimePtr(row.PublishAt),
+		ViewCount:   row.ViewCount,
+		Slug:        row.Slug,
+		Tags:        articleTags(row.Tags),
+		Series:      newArticleSeries(row),
+	}, nil
+}
+
+// articleTags returns the tags of an article row, serializing articles without tags as an empty list
+func articleTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// newArticleSeries returns the series an article row belongs to, or nil when it is not part of a series
+func newArticleSeries(row database.GetArticleRow) *ArticleSeries {
+	if !row.SeriesID.Valid {
+		return nil
+	}
+	return &ArticleSeries{
+		ID:       row.SeriesID.UUID,
+		Title:    row.SeriesTitle.String,
+		Position: row.SeriesPosition.Int32,
+	}
+}
+
+// validArticleSorts lists the accepted values of the sort query parameter
+var validArticleSorts = map[string]bool{
+	"newest":      true,
+	"oldest":      true,
+	"title":       true,
+	"most_viewed": true,
+}
+
+// parseArticleFilters reads the user_id, category, from, to, status and tag query parameters
+// into the filters shared by the article listing and count queries
+func parseArticleFilters(r *http.Request) (database.CountArticlesParams, error) {
+	filters := database.CountArticlesParams{
+		ViewerID: viewerID(r), // drafts are only listed for their author
+	}
+
+	var err error
+	filters.UserID, err = parseUUIDParam(r.URL.Query().Get("user_id"))
+	if err != nil {
+		return filters, fmt.Errorf("invalid author ID: %w", err)
+	}
+	filters.CategoryID, filters.CategoryName = parseCategoryParam(r.URL.Query().Get("category"))
+	filters.CreatedFrom, err = parseDateParam(r.URL.Query().Get("from"), false)
+	if err != nil {
+		return filters, fmt.Errorf("invalid from date: %w", err)
+	}
+	filters.CreatedTo, err = parseDateParam(r.URL.Query().Get("to"), true)
+	if err != nil {
+		return filters, fmt.Errorf("invalid to date: %w", err)
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		if _, ok := allowedStatusTransitions[status]; !ok {
+			return filters, errors.New("invalid status: " + status)
+		}
+		filters.Status = sql.NullString{String: status, Valid: true}
+	}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		filters.Tag = sql.NullString{String: slugify(tag), Valid: true} // tags are matched by their slug
+	}
+	return filters, nil
+}
@@ -0,0 +1,416 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// articleReadRequests are the article read endpoints with the number of queries each of them may run
+var articleReadRequests = []struct {
+	name        string
+	target      string
+	articleID   string // path value for the detail endpoint
+	inSeries    bool   // whether the canned articles belong to a series
+	handler     func(cfg *APIConfig) http.HandlerFunc
+	wantQueries int64
+}{
+	{
+		name:        "Article detail",
+		target:      "/api/articles/6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c",
+		articleID:   "6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c",
+		handler:     func(cfg *APIConfig) http.HandlerFunc { return cfg.handlerArticlesGet },
+		wantQueries: 1,
+	},
+	{
+		name:        "Article detail in a series",
+		target:      "/api/articles/6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c",
+		articleID:   "6f1c2a0e-8d4b-4f7a-9c3e-2b5d7e9f1a3c",
+		inSeries:    true,
+		handler:     func(cfg *APIConfig) http.HandlerFunc { return cfg.handlerArticlesGet },
+		wantQueries: 1, // the previous and next article in the series come with the article
+	},
+	{
+		name:        "Article listing by page",
+		target:      "/api/articles?page=1&limit=20",
+		handler:     func(cfg *APIConfig) http.HandlerFunc { return cfg.handlerArticlesRetrieve },
+		wantQueries: 2, // the total count and the page itself
+	},
+	{
+		name:        "Article listing by cursor",
+		target:      "/api/articles?cursor=&limit=20",
+		handler:     func(cfg *APIConfig) http.HandlerFunc { return cfg.handlerArticlesRetrieve },
+		wantQueries: 1,
+	},
+}
+
+func TestArticleQueriesPerRequest(t *testing.T) {
+	token, err := auth.MakeJWT(uuid.New(), uuid.New(), "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	for _, tt := range articleReadRequests {
+		for _, viewer := range []struct {
+			name  string
+			token string
+		}{{"anonymous", ""}, {"signed in", token}} {
+			t.Run(tt.name+" "+viewer.name, func(t *testing.T) {
+				cfg, connector := newCountingConfig(20)
+				connector.inSeries = tt.inSeries
+				// through the middleware of the routes, which must not add queries of its own for signed in readers
+				handler := cfg.middlewareOptionalAuth(tt.handler(cfg))
+				req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+				if tt.articleID != "" {
+					req.SetPathValue("articleID", tt.articleID)
+				}
+				if viewer.token != "" {
+					req.Header.Set("Authorization", "Bearer "+viewer.token)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+				}
+				if got := connector.queries.Load(); got != tt.wantQueries {
+					t.Errorf("queries = %d, want %d", got, tt.wantQueries)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkArticleRequests(b *testing.B) {
+	for _, bb := range articleReadRequests {
+		b.Run(bb.name, func(b *testing.B) {
+			cfg, connector := newCountingConfig(20)
+			connector.inSeries = bb.inSeries
+			handler := bb.handler(cfg)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				serveArticleRequest(cfg, handler, bb.target, bb.articleID)
+			}
+			b.ReportMetric(float64(connector.queries.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+// serveArticleRequest runs a GET request through the handler and returns the response status
+func serveArticleRequest(cfg *APIConfig, handler http.HandlerFunc, target, articleID string) int {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if articleID != "" {
+		req.SetPathValue("articleID", articleID)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec.Code
+}
+
+// newCountingConfig returns an APIConfig backed by a countingConnector whose listing queries return the given number of rows
+func newCountingConfig(rows int) (*APIConfig, *countingConnector) {
+	connector := &countingConnector{rows: rows}
+	return NewAPIConfig(sql.OpenDB(connector), "dev", "secret"), connector
+}
+
+// countingConnector is a database/sql connector that answers every query with made up rows and counts the round trips,
+// so the number of queries behind a request can be measured without a Postgres server
+type countingConnector struct {
+	queries  atomic.Int64
+	rows     int  // number of rows returned by the article listing queries
+	inSeries bool // whether the canned articles belong to a series, they are not part of one by default
+	untagged bool // whether the canned articles have no tags, they have two by default
+
+	revokedSessions sync.Map // IDs of the sessions revoked through RevokeSession, every other session is active
+	deniedTokens    sync.Map // jti of the access tokens denied through DenyAccessToken
+	sessionUsers    sync.Map // users of the sessions that RevokeAllSessions ends, by session ID
+	revokedUsers    sync.Map // IDs of the users whose sessions were all revoked through RevokeAllSessions
+	resetTokens     sync.Map // password reset tokens by hash, for UsePasswordResetToken
+}
+
+// cannedResetToken is a password reset token known to the countingConnector
+type cannedResetToken struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+	used      bool
+}
+
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &countingConn{connector: c}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver {
+	return countingDriver{}
+}
+
+type countingDriver struct{}
+
+func (countingDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("counting driver must be used through its connector")
+}
+
+type countingConn struct {
+	connector *countingConnector
+}
+
+func (c *countingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *countingConn) Close() error {
+	return nil
+}
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return countingTx{}, nil
+}
+
+// countingTx lets queries run in transactions, there is nothing to commit or roll back
+type countingTx struct{}
+
+func (countingTx) Commit() error {
+	return nil
+}
+
+func (countingTx) Rollback() error {
+	return nil
+}
+
+func (c *countingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.connector.queries.Add(1)
+	if strings.HasPrefix(query, "-- name: RevokeSession ") {
+		c.connector.revokedSessions.Store(args[0].Value, true)
+	}
+	if strings.HasPrefix(query, "-- name: RevokeAllSessions ") {
+		c.connector.revokedUsers.Store(args[0].Value, true)
+	}
+	if strings.HasPrefix(query, "-- name: DenyAccessToken ") {
+		c.connector.deniedTokens.Store(args[0].Value, true)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *countingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.connector.queries.Add(1)
+	if strings.HasPrefix(query, "-- name: IsAccessTokenActive ") {
+		_, denied := c.connector.deniedTokens.Load(args[0].Value)
+		_, revoked := c.connector.revokedSessions.Load(args[1].Value)
+		if userID, ok := c.connector.sessionUsers.Load(args[1].Value); ok {
+			_, allRevoked := c.connector.revokedUsers.Load(userID)
+			revoked = revoked || allRevoked
+		}
+		return &cannedRows{columns: []string{"active"}, remaining: 1, active: !denied && !revoked}, nil
+	}
+	if strings.HasPrefix(query, "-- name: UsePasswordResetToken ") {
+		value, ok := c.connector.resetTokens.Load(args[0].Value)
+		if !ok || value.(*cannedResetToken).used || !value.(*cannedResetToken).expiresAt.After(time.Now()) {
+			return &valueRows{columns: []string{"token_hash"}}, nil
+		}
+		token := value.(*cannedResetToken)
+		token.used = true
+		return &valueRows{
+			columns: []string{"token_hash", "created_at", "user_id", "expires_at", "used_at"},
+			values:  [][]driver.Value{{args[0].Value, cannedTime, token.userID.String(), token.expiresAt, time.Now()}},
+		}, nil
+	}
+	rows := 1
+	if strings.HasPrefix(query, "-- name: GetArticles") {
+		rows = c.connector.rows
+	}
+	return &cannedRows{columns: selectedColumns(query), remaining: rows, count: int64(c.connector.rows), inSeries: c.connector.inSeries, untagged: c.connector.untagged}, nil
+}
+
+var (
+	selectListPattern      = regexp.MustCompile(`(?is)SELECT (.*?)\nFROM`)
+	shortSelectListPattern = regexp.MustCompile(`SELECT (.*?) FROM`) // queries with the FROM on the select line
+	returningListPattern   = regexp.MustCompile(`(?m)^RETURNING (.*)$`)
+	selectSeparatorPattern = regexp.MustCompile(`,\s+`)
+)
+
+// selectedColumns returns the names of the columns in the returning or select list of a query
+func selectedColumns(query string) []string {
+	match := returningListPattern.FindStringSubmatch(query)
+	if match == nil {
+		match = selectListPattern.FindStringSubmatch(query)
+	}
+	if match == nil {
+		match = shortSelectListPattern.FindStringSubmatch(query)
+	}
+	if match == nil {
+		return nil
+	}
+	columns := []string{}
+	for _, expr := range selectSeparatorPattern.Split(match[1], -1) {
+		fields := strings.Fields(expr)
+		name := fields[len(fields)-1]                // the alias when there is one
+		name = name[strings.LastIndex(name, ".")+1:] // strip the table alias
+		if strings.HasPrefix(name, "COUNT(") {
+			name = "count"
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// cannedTime is the value of every timestamp column, fixed so that responses can be compared across requests
+var cannedTime = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// cannedRows returns the same made up values for every row
+type cannedRows struct {
+	columns   []string
+	remaining int
+	count     int64 // value of COUNT(*) columns
+	inSeries  bool  // whether the series columns are filled in or NULL
+	untagged  bool  // whether the tags column is NULL
+	active    bool  // value of the active column of IsAccessTokenActive
+}
+
+func (r *cannedRows) Columns() []string {
+	return r.columns
+}
+
+func (r *cannedRows) Close() error {
+	return nil
+}
+
+func (r *cannedRows) Next(dest []driver.Value) error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	r.remaining--
+	for i, column := range r.columns {
+		switch column {
+		case "id", "user_id", "category_id":
+			dest[i] = uuid.NewSHA1(uuid.NameSpaceOID, []byte(column+strconv.Itoa(r.remaining))).String() // the same for every request
+		case "created_at", "updated_at", "published_at", "expires_at", "last_used_at":
+			dest[i] = cannedTime
+		case "title", "slug", "username", "category_name", "hashed_password", "token_hash", "user_agent", "ip", "name", "description":
+			dest[i] = "Example " + column
+		case "email":
+			dest[i] = "reader@example.com"
+		case "is_admin":
+			dest[i] = false
+		case "status":
+			dest[i] = ArticleStatusPublished
+		case "body":
+			dest[i] = []byte("{}")
+		case "tags":
+			dest[i] = []byte("{go,web}")
+			if r.untagged {
+				dest[i] = nil
+			}
+		case "view_count":
+			dest[i] = int64(0)
+		case "position":
+			dest[i] = int64(1)
+		case "count":
+			dest[i] = r.count
+		case "active":
+			dest[i] = r.active
+		case "series_id", "previous_id", "next_id":
+			dest[i] = nil
+			if r.inSeries {
+				dest[i] = uuid.NewSHA1(uuid.NameSpaceOID, []byte(column)).String()
+			}
+		case "series_title", "previous_title", "next_title":
+			dest[i] = nil
+			if r.inSeries {
+				dest[i] = "Example " + column
+			}
+		case "previous_status", "next_status":
+			dest[i] = nil
+			if r.inSeries {
+				dest[i] = ArticleStatusPublished
+			}
+		case "series_position", "previous_position", "next_position":
+			dest[i] = nil
+			if r.inSeries {
+				dest[i] = int64(2)
+			}
+		default:
+			dest[i] = nil // nullable columns
+		}
+	}
+	return nil
+}
+
+// valueRows returns the rows it is given
+type valueRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *valueRows) Columns() []string {
+	return r.columns
+}
+
+func (r *valueRows) Close() error {
+	return nil
+}
+
+func (r *valueRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestArticleRowOf(t *testing.T) {
+	// every column GetArticle shares with the view and search rows, set to something other than its zero value
+	want := database.GetArticleRow{
+		ID:             uuid.New(),
+		CreatedAt:      cannedTime,
+		UpdatedAt:      cannedTime.Add(time.Hour),
+		UserID:         uuid.New(),
+		CategoryID:     uuid.New(),
+		Title:          "Getting started",
+		Body:           json.RawMessage(`{"version": 2, "blocks": []}`),
+		ImageUrl:       sql.NullString{String: "/api/uploads/go.png", Valid: true},
+		Status:         ArticleStatusPublished,
+		PublishedAt:    sql.NullTime{Time: cannedTime, Valid: true},
+		PublishAt:      sql.NullTime{Time: cannedTime, Valid: true},
+		ViewCount:      7,
+		Slug:           "getting-started",
+		SearchVector:   "'go':1",
+		Username:       "gopher",
+		CategoryName:   "Programming",
+		Tags:           []string{"go"},
+		SeriesID:       uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		SeriesTitle:    sql.NullString{String: "Go basics", Valid: true},
+		SeriesPosition: sql.NullInt32{Int32: 2, Valid: true},
+	}
+	value := reflect.ValueOf(want)
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).IsZero() {
+			t.Fatalf("%s is not set in the test row", value.Type().Field(i).Name)
+		}
+	}
+
+	for _, row := range []any{&database.ViewArticleRow{}, &database.SearchArticlesRow{}} {
+		target := reflect.ValueOf(row).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			target.FieldByName(value.Type().Field(i).Name).Set(value.Field(i))
+		}
+		if got := articleRowOf(target.Interface()); !reflect.DeepEqual(got, want) {
+			t.Errorf("articleRowOf(%T) = %+v, want %+v", target.Interface(), got, want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxArticleSlugLength   = 80 // longest slug derived from a title, before a suffix is added
+	maxArticleSlugAttempts = 3  // times a new article tries another slug when the one it picked was just taken
+)
+
+// reservedArticleSlugs are taken by the routes under /api/articles/{articleID}/, which would shadow
+// /api/articles/by-slug/{slug} for them
+var reservedArticleSlugs = []string{"comments", "revisions"}
+
+// Handler function to retrieve an article by its slug, slugs the article had before its title changed
+// redirect to the current one. It is routed as /api/articles/{articleID}/{slug}, a literal by-slug segment would
+// conflict with /api/articles/{articleID}/comments and the like, so every other first segment is not found.
+func (cfg *APIConfig) handlerArticlesGetBySlug(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("articleID") != "by-slug" {
+		respondWithError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	slug := r.PathValue("slug") // Extract the slug from the URL path
+
+	if cfg.respondWithArticleView(w, r, database.ViewArticleParams{Slug: sql.NullString{String: slug, Valid: true}}) {
+		return
+	}
+
+	// Not a current slug, look it up among the slugs articles used to have
+	currentSlug, err := cfg.db.GetArticleSlugRedirect(r.Context(), database.GetArticleSlugRedirectParams{
+		Slug:     slug,
+		ViewerID: viewerID(r),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Article not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+
+	// Keep the query string, so a redirected request for ?format=html still gets the rendered article
+	target := url.URL{Path: "/api/articles/by-slug/" + currentSlug, RawQuery: r.URL.RawQuery}
+	http.Redirect(w, r, target.String(), http.StatusMovedPermanently)
+}
+
+// nextArticleSlug returns a slug for the title that no other article uses or used before, "My Title" becomes
+// "my-title", or "my-title-2", "my-title-3" and so on when that is taken. Slugs the article with the given ID
+// has or had are free for it to use, pass uuid.Nil for an article that does not exist yet.
+func (cfg *APIConfig) nextArticleSlug(ctx context.Context, title string, articleID uuid.UUID) (string, error) {
+	base := slugify(title)
+	if runes := []rune(base); len(runes) > maxArticleSlugLength {
+		base = strings.TrimRight(string(runes[:maxArticleSlugLength]), "-")
+	}
+	if base == "" {
+		base = "article" // titles without letters or digits
+	}
+
+	taken, err := cfg.db.GetTakenArticleSlugs(ctx, database.GetTakenArticleSlugsParams{
+		Slug:      base,
+		ArticleID: articleID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	slug := base
+	for n := 2; slices.Contains(taken, slug) || slices.Contains(reservedArticleSlugs, slug); n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug, nil
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestArticlesGetBySlugRoute(t *testing.T) {
+	tests := []struct {
+		target      string
+		want        int
+		wantQueries int64
+	}{
+		{target: "/api/articles/by-slug/getting-started", want: http.StatusOK, wantQueries: 1},
+		{target: "/api/articles/by-name/getting-started", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			cfg, connector := newCountingConfig(0)
+			mux := http.NewServeMux()
+			cfg.SetupRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := connector.queries.Load(); got != tt.wantQueries {
+				t.Errorf("queries = %d, want %d", got, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestNextArticleSlugSkipsRouteSegments(t *testing.T) {
+	cfg, _ := newCountingConfig(0)
+	for title, want := range map[string]string{"Comments": "comments-2", "Revisions": "revisions-2", "Comments on Go": "comments-on-go"} {
+		got, err := cfg.nextArticleSlug(context.Background(), title, uuid.Nil)
+		if err != nil {
+			t.Fatalf("nextArticleSlug(%q) error = %v", title, err)
+		}
+		if got != want {
+			t.Errorf("nextArticleSlug(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Article statuses, drafts and scheduled articles that are not yet due are only visible to their author
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusScheduled = "scheduled"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+// allowedStatusTransitions maps the current status of an article to the statuses it may move to
+var allowedStatusTransitions = map[string][]string{
+	ArticleStatusDraft:     {ArticleStatusScheduled, ArticleStatusPublished},
+	ArticleStatusScheduled: {ArticleStatusDraft, ArticleStatusScheduled, ArticleStatusPublished}, // scheduled to scheduled moves the publish time
+	ArticleStatusPublished: {ArticleStatusDraft, ArticleStatusArchived},
+	ArticleStatusArchived:  {ArticleStatusPublished},
+}
+
+// Handler function to move an article between the draft, scheduled, published and archived statuses
+func (cfg *APIConfig) handlerArticlesStatus(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Status    string     `json:"status"`
+		PublishAt *time.Time `json:"publish_at"` // required when scheduling an article
+	}
+
+	// Extract the article ID from the URL
+	articleID, err := uuid.Parse(r.PathValue("articleID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	// Retrieve the article from the database using the article ID
+	dbArticle, err := cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       articleID,
+		ViewerID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Article not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+	// Check if the user ID from the token matches the user ID of the article
+	if dbArticle.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not change the status of this article", nil)
+		return
+	}
+
+	if !canTransitionStatus(dbArticle.Status, params.Status) {
+		respondWithError(w, http.StatusConflict, "Can not change article status from "+dbArticle.Status+" to "+params.Status, nil)
+		return
+	}
+
+	publishAt := sql.NullTime{}
+	if params.Status == ArticleStatusScheduled {
+		if params.PublishAt == nil || !params.PublishAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "Scheduling an article requires a publish_at in the future", nil)
+			return
+		}
+		publishAt = sql.NullTime{Time: params.PublishAt.UTC(), Valid: true}
+	}
+
+	// Save the new status, published_at is set the first time an article is published
+	article, err := cfg.db.UpdateArticleStatus(r.Context(), database.UpdateArticleStatusParams{
+		ID:        articleID,
+		Status:    params.Status,
+		PublishAt: publishAt, // only stored when scheduling
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update article status", err)
+		return
+	}
+
+	body, err := decodeArticleBody(article.Body) // Unmarshal the article body from JSON into the struct
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Article{
+		ID:          article.ID,
+		CreatedAt:   article.CreatedAt,
+		UpdatedAt:   article.UpdatedAt,
+		UserID:      article.UserID,
+		Category:    dbArticle.CategoryName, // the status change does not move the article to another category
+		Title:       article.Title,
+		Body:        body,
+		ImageUrl:    article.ImageUrl.String,
+		Username:    dbArticle.Username,
+		Status:      article.Status,
+		PublishedAt: nullTimePtr(article.PublishedAt),
+		PublishAt:   nullTimePtr(article.PublishAt),
+		ViewCount:   article.ViewCount,
+		Slug:        article.Slug,
+		Tags:        articleTags(dbArticle.Tags),
+		Series:      newArticleSeries(dbArticle),
+	})
+}
+
+func canTransitionStatus(from, to string) bool { // Function to check if an article may move from one status to another
+	for _, status := range allowedStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// nullTimePtr converts a sql.NullTime into a pointer, so missing timestamps serialize as null
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to update an existing article, fields left out of the request keep their current values
+func (cfg *APIConfig) handlerArticlesUpdate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Title       *string      `json:"title"`
+		ArticleBody *ArticleBody `json:"article_body"`
+		ImageUrl    *string      `json:"image_url"`
+		CategoryID  *uuid.UUID   `json:"category_id"`
+	}
+
+	// Extract the article ID from the URL
+	articleIDString := r.PathValue("articleID")
+	articleID, err := uuid.Parse(articleIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	// Retrieve the article from the database using the article ID
+	dbArticle, err := cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       articleID,
+		ViewerID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Article not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+	// Check if the user ID from the token matches the user ID of the article
+	if dbArticle.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not edit this article", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	// Start from the stored article and apply the provided fields on top
+	title := dbArticle.Title
+	if params.Title != nil {
+		if *params.Title == "" {
+			respondWithError(w, http.StatusBadRequest, "Title can not be empty", nil)
+			return
+		}
+		title = *params.Title
+	}
+
+	// Only a body in the request is validated, the stored one is saved again as it is
+	var body ArticleBody
+	bodyJSON := dbArticle.Body
+	if params.ArticleBody != nil {
+		body, err = validateArticle(*params.ArticleBody) // Validate the article body
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
+			return
+		}
+		bodyJSON, err = json.Marshal(body) // Marshal the cleaned body to JSON
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to marshal article body", err)
+			return
+		}
+	} else {
+		body, err = decodeArticleBody(dbArticle.Body)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+			return
+		}
+	}
+
+	imageUrl := dbArticle.ImageUrl
+	if params.ImageUrl != nil {
+		imageUrl = sql.NullString{
+			String: *params.ImageUrl,
+			Valid:  *params.ImageUrl != "", // an empty string removes the image
+		}
+	}
+
+	categoryID := dbArticle.CategoryID
+	if params.CategoryID != nil {
+		categoryID = *params.CategoryID
+	}
+
+	// Make sure the category exists before saving
+	category, err := cfg.db.GetCategoryByID(r.Context(), categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Invalid category ID", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve category", err)
+		return
+	}
+
+	// A new title gets a new slug, the query keeps the old one around so it redirects to the article
+	slug := dbArticle.Slug
+	if title != dbArticle.Title {
+		slug, err = cfg.nextArticleSlug(r.Context(), title, articleID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to generate article slug", err)
+			return
+		}
+	}
+
+	// Save the changes to the database, updated_at is bumped by the query
+	article, err := cfg.db.UpdateArticle(r.Context(), database.UpdateArticleParams{
+		ID:         articleID,
+		CategoryID: categoryID,
+		Title:      title,
+		Body:       bodyJSON,
+		ImageUrl:   imageUrl,
+		Slug:       slug,
+	})
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			respondWithError(w, http.StatusConflict, "Another article took the slug for this title, please try again", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update article", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Article{
+		ID:          article.ID,
+		CreatedAt:   article.CreatedAt,
+		UpdatedAt:   article.UpdatedAt,
+		UserID:      article.UserID,
+		Category:    category.Name,
+		Title:       article.Title,
+		Body:        body,
+		ImageUrl:    article.ImageUrl.String,
+		Username:    dbArticle.Username,
+		Status:      article.Status,
+		PublishedAt: nullTimePtr(article.PublishedAt),
+		PublishAt:   nullTimePtr(article.PublishAt),
+		ViewCount:   article.ViewCount,
+		Slug:        article.Slug,
+		Tags:        articleTags(dbArticle.Tags),
+		Series:      newArticleSeries(dbArticle),
+	})
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestArticlesUpdateValidatesOnlySentFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "Title only", body: `{"title": "A new title"}`, want: http.StatusOK},
+		{name: "Invalid body", body: `{"article_body": {"version": 2, "blocks": [{"type": "paragraph"}]}}`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The canned article has neither tags nor blocks, its empty legacy body would not pass validation
+			cfg, connector := newCountingConfig(0)
+			connector.untagged = true
+			articleID := uuid.New().String()
+			owner := uuid.NewSHA1(uuid.NameSpaceOID, []byte("user_id0")) // user_id of the canned row
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/articles/"+articleID, strings.NewReader(tt.body))
+			req.SetPathValue("articleID", articleID)
+			req = req.WithContext(context.WithValue(req.Context(), "userID", owner))
+			rec := httptest.NewRecorder()
+			cfg.handlerArticlesUpdate(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var article map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &article); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got := string(article["tags"]); got != "[]" {
+				t.Errorf("tags = %s, want []", got)
+			}
+		})
+	}
+}
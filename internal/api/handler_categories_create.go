@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxCategoryNameLength        = 100  // maximum number of characters in a category name
+	maxCategoryDescriptionLength = 1000 // maximum number of characters in a category description
+)
+
+// Handler function to create a new category, it is placed after the existing categories
+func (cfg *APIConfig) handlerCategoriesCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Name        string     `json:"name"`
+		Slug        string     `json:"slug"` // derived from the name when left empty
+		Description string     `json:"description"`
+		ParentID    *uuid.UUID `json:"parent_id"` // nil for a top level category
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	if params.Slug == "" {
+		params.Slug = slugify(params.Name)
+	}
+	category := database.Category{
+		Name:        strings.TrimSpace(params.Name),
+		Slug:        params.Slug,
+		Description: strings.TrimSpace(params.Description),
+	}
+	if params.ParentID != nil {
+		category.ParentID = uuid.NullUUID{UUID: *params.ParentID, Valid: true}
+	}
+	if err := cfg.validateCategory(r, category); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
+		return
+	}
+
+	dbCategory, err := cfg.db.CreateCategory(r.Context(), database.CreateCategoryParams{
+		Name:        category.Name,
+		ParentID:    category.ParentID,
+		Slug:        category.Slug,
+		Description: category.Description,
+	})
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			respondWithError(w, http.StatusConflict, "A category with this name or slug already exists", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create category", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newCategory(dbCategory))
+}
+
+// validateCategory checks the fields of a category that is about to be saved,
+// a parent has to exist and can not be the category itself or one of its descendants
+func (cfg *APIConfig) validateCategory(r *http.Request, category database.Category) error {
+	if category.Name == "" {
+		return errors.New("name is required")
+	}
+	if utf8.RuneCountInString(category.Name) > maxCategoryNameLength {
+		return errors.New("name is too long")
+	}
+	if category.Slug == "" || slugify(category.Slug) != category.Slug {
+		return errors.New("slug must consist of lowercase letters and digits separated by single hyphens")
+	}
+	if utf8.RuneCountInString(category.Description) > maxCategoryDescriptionLength {
+		return errors.New("description is too long")
+	}
+	if !category.ParentID.Valid {
+		return nil
+	}
+
+	categories, err := cfg.db.GetCategories(r.Context())
+	if err != nil {
+		return err
+	}
+	parents := make(map[uuid.UUID]uuid.NullUUID, len(categories)) // parent of every category by ID
+	for _, cat := range categories {
+		parents[cat.ID] = cat.ParentID
+	}
+	if _, ok := parents[category.ParentID.UUID]; !ok {
+		return errors.New("parent category does not exist")
+	}
+	// Walk up from the new parent, reaching the category itself means the move would create a cycle
+	for id := category.ParentID; id.Valid; id = parents[id.UUID] {
+		if id.UUID == category.ID {
+			return errors.New("a category can not be moved below itself or one of its subcategories")
+		}
+	}
+	return nil
+}
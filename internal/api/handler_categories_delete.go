@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to delete a category. Categories that still have articles can only be deleted
+// when the reassign_to query parameter names the category their articles should move to.
+// Subcategories move up to the parent of the deleted category.
+func (cfg *APIConfig) handlerCategoriesDelete(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := uuid.Parse(r.PathValue("categoryID")) // Extract the category ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid category ID", err)
+		return
+	}
+
+	reassignTo, err := parseUUIDParam(r.URL.Query().Get("reassign_to"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid reassign_to category ID", err)
+		return
+	}
+
+	var deleted int64
+	if reassignTo.Valid {
+		if reassignTo.UUID == categoryID {
+			respondWithError(w, http.StatusBadRequest, "Can not reassign articles to the category being deleted", nil)
+			return
+		}
+		// Move the articles and delete the category in a single statement
+		deleted, err = cfg.db.DeleteCategoryReassigningArticles(r.Context(), database.DeleteCategoryReassigningArticlesParams{
+			ReassignTo: reassignTo.UUID,
+			ID:         categoryID,
+		})
+	} else {
+		deleted, err = cfg.db.DeleteCategory(r.Context(), categoryID)
+	}
+	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			if reassignTo.Valid {
+				respondWithError(w, http.StatusBadRequest, "The reassign_to category does not exist", err)
+				return
+			}
+			respondWithError(w, http.StatusConflict, "Category still has articles, use reassign_to to move them", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete category", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Category not found", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Respond with 204 No Content
+}
@@ -1,33 +1,113 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 
+	"github.com/GitIBB/pursuit/internal/database"
 	"github.com/google/uuid"
 )
 
+type Category struct { // struct to hold category data
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"` // nil for top level categories
+	Position    int32      `json:"position"`  // siblings are listed by ascending position
+}
+
+type CategoryNode struct { // struct to hold a category with its subcategories
+	Category
+	ArticleCount      int64          `json:"article_count"`       // published articles directly in this category
+	TotalArticleCount int64          `json:"total_article_count"` // published articles in this category and all of its descendants
+	Children          []CategoryNode `json:"children"`
+}
+
+// Handler function to retrieve the category tree with the number of published articles in every category
 func (cfg *APIConfig) handlerCategoriesGet(w http.ResponseWriter, r *http.Request) {
 	// Retrieve the categories from the database
-	categories, err := cfg.db.GetCategories(r.Context())
+	categories, err := cfg.db.GetCategoriesWithArticleCounts(r.Context())
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve categories", err)
 		return
 	}
 
-	type CategoryResponse struct {
-		ID   uuid.UUID `json:"id"`
-		Name string    `json:"name"`
+	// Group the categories by their parent, keeping the order of the query
+	children := make(map[uuid.UUID][]CategoryNode) // top level categories are grouped under uuid.Nil
+	for _, cat := range categories {
+		node := CategoryNode{
+			Category: newCategory(database.Category{
+				ID:          cat.ID,
+				Name:        cat.Name,
+				Position:    cat.Position,
+				ParentID:    cat.ParentID,
+				Slug:        cat.Slug,
+				Description: cat.Description,
+			}),
+			ArticleCount: cat.ArticleCount,
+		}
+		children[cat.ParentID.UUID] = append(children[cat.ParentID.UUID], node) // an invalid ParentID holds uuid.Nil
 	}
 
-	catResp := make([]CategoryResponse, len(categories))
-	for i, cat := range categories {
-		catResp[i] = CategoryResponse{
-			ID:   cat.ID,
-			Name: cat.Name,
+	var attach func(nodes []CategoryNode) []CategoryNode
+	attach = func(nodes []CategoryNode) []CategoryNode {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+			nodes[i].TotalArticleCount = nodes[i].ArticleCount
+			for _, child := range nodes[i].Children {
+				nodes[i].TotalArticleCount += child.TotalArticleCount
+			}
 		}
-
+		if nodes == nil {
+			return []CategoryNode{}
+		}
+		return nodes
 	}
 
 	// Respond with the categories in JSON format
-	respondWithJSON(w, http.StatusOK, catResp)
+	respondWithJSON(w, http.StatusOK, attach(children[uuid.Nil]))
+}
+
+// Handler function to retrieve the articles in a category and all of its descendants,
+// supports the same filters, sorts and pagination as the article listing
+func (cfg *APIConfig) handlerCategoryArticles(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug") // Extract the category slug from the URL
+
+	// Make sure the category exists, so an unknown category is not mistaken for one without articles
+	_, err := cfg.db.GetCategoryBySlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Category not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve category", err)
+		return
+	}
+
+	filters, err := parseArticleFilters(r) // parse query parameters for filtering
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	filters.CategorySlug = sql.NullString{String: slug, Valid: true}
+
+	cfg.retrieveArticles(w, r, filters)
+}
+
+// newCategory builds the Category response from a database category
+func newCategory(cat database.Category) Category {
+	category := Category{
+		ID:          cat.ID,
+		Name:        cat.Name,
+		Slug:        cat.Slug,
+		Description: cat.Description,
+		Position:    cat.Position,
+	}
+	// Handle uuid.NullUUID for ParentID
+	if cat.ParentID.Valid {
+		category.ParentID = &cat.ParentID.UUID
+	}
+	return category
 }
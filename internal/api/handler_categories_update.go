@@ -0,0 +1,142 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to change the name, slug, description or parent of a category, omitted fields are left unchanged.
+// The slug does not follow a new name, so links to the category keep working.
+func (cfg *APIConfig) handlerCategoriesUpdate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Name        *string         `json:"name"`
+		Slug        *string         `json:"slug"`
+		Description *string         `json:"description"`
+		ParentID    json.RawMessage `json:"parent_id"` // null moves the category to the top level
+	}
+
+	categoryID, err := uuid.Parse(r.PathValue("categoryID")) // Extract the category ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid category ID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	category, err := cfg.db.GetCategoryByID(r.Context(), categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Category not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve category", err)
+		return
+	}
+
+	// Start from the stored category and apply the provided fields on top
+	if params.Name != nil {
+		category.Name = strings.TrimSpace(*params.Name)
+	}
+	if params.Slug != nil {
+		category.Slug = *params.Slug
+	}
+	if params.Description != nil {
+		category.Description = strings.TrimSpace(*params.Description)
+	}
+	if len(params.ParentID) > 0 {
+		var parentID *uuid.UUID
+		err = json.Unmarshal(params.ParentID, &parentID)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid parent ID", err)
+			return
+		}
+		category.ParentID = uuid.NullUUID{}
+		if parentID != nil {
+			category.ParentID = uuid.NullUUID{UUID: *parentID, Valid: true}
+		}
+	}
+	if err := cfg.validateCategory(r, category); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
+		return
+	}
+
+	dbCategory, err := cfg.db.UpdateCategory(r.Context(), database.UpdateCategoryParams{
+		ID:          categoryID,
+		Name:        category.Name,
+		ParentID:    category.ParentID,
+		Slug:        category.Slug,
+		Description: category.Description,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Category not found", err)
+			return
+		}
+		if isPQError(err, pqUniqueViolation) {
+			respondWithError(w, http.StatusConflict, "A category with this name or slug already exists", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update category", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newCategory(dbCategory))
+}
+
+// Handler function to change the display order of the categories, every category has to be listed exactly once
+func (cfg *APIConfig) handlerCategoriesReorder(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		CategoryIDs []uuid.UUID `json:"category_ids"` // categories in their new order
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	categories, err := cfg.db.GetCategories(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve categories", err)
+		return
+	}
+
+	// A partial list would leave the other categories with clashing positions
+	remaining := make(map[uuid.UUID]bool, len(categories))
+	for _, cat := range categories {
+		remaining[cat.ID] = true
+	}
+	for _, id := range params.CategoryIDs {
+		if !remaining[id] {
+			respondWithError(w, http.StatusBadRequest, "category_ids contains an unknown or repeated category: "+id.String(), nil)
+			return
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		respondWithError(w, http.StatusBadRequest, "category_ids must list every category", nil)
+		return
+	}
+
+	err = cfg.db.ReorderCategories(r.Context(), params.CategoryIDs)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to reorder categories", err)
+		return
+	}
+
+	cfg.handlerCategoriesGet(w, r) // Respond with the category tree in its new order
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+const maxCommentLength = 5000 // maximum number of characters in a comment
+
+type Comment struct { // struct to hold comment data
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ArticleID uuid.UUID  `json:"article_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	ParentID  *uuid.UUID `json:"parent_id"` // nil for top level comments
+	Body      string     `json:"body"`
+	Username  string     `json:"username"` // Username of the author, can be retrieved from the database user table
+	Replies   []Comment  `json:"replies"`
+}
+
+// Handler function to create a new comment (or a reply to a comment) on an article
+func (cfg *APIConfig) handlerCommentsCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body     string     `json:"body"`
+		ParentID *uuid.UUID `json:"parent_id"`
+	}
+
+	// Extract the article ID from the URL
+	articleID, err := uuid.Parse(r.PathValue("articleID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	body, err := validateComment(params.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
+		return
+	}
+
+	// Make sure the article exists and is visible to the user
+	_, err = cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       articleID,
+		ViewerID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Article not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+
+	// Replies must point to a comment on the same article
+	parentID := uuid.NullUUID{}
+	if params.ParentID != nil {
+		parent, err := cfg.db.GetComment(r.Context(), *params.ParentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Parent comment not found", err)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve parent comment", err)
+			return
+		}
+		if parent.ArticleID != articleID {
+			respondWithError(w, http.StatusBadRequest, "Parent comment belongs to a different article", nil)
+			return
+		}
+		parentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
+	// Save the comment to the database
+	comment, err := cfg.db.CreateComment(r.Context(), database.CreateCommentParams{
+		ArticleID: articleID,
+		UserID:    userID,
+		ParentID:  parentID,
+		Body:      body,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create comment", err)
+		return
+	}
+
+	// retrieve username from the database
+	user, err := cfg.db.GetUserByID(r.Context(), comment.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch username", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newComment(comment.ID, comment.CreatedAt, comment.UpdatedAt, comment.ArticleID, comment.UserID, comment.ParentID, comment.Body, user.Username))
+}
+
+// newComment builds the Comment response from the columns shared by the comment query rows
+func newComment(id uuid.UUID, createdAt, updatedAt time.Time, articleID, userID uuid.UUID, parentID uuid.NullUUID, body, username string) Comment {
+	comment := Comment{
+		ID:        id,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		ArticleID: articleID,
+		UserID:    userID,
+		Body:      body,
+		Username:  username,
+		Replies:   []Comment{},
+	}
+	// Handle uuid.NullUUID for ParentID
+	if parentID.Valid {
+		comment.ParentID = &parentID.UUID
+	}
+	return comment
+}
+
+func validateComment(body string) (string, error) { // Function to validate the comment body
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return body, errors.New("body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return body, errors.New("body is too long")
+	}
+	return body, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Handler function to delete a comment, replies to the comment are removed along with it
+func (cfg *APIConfig) handlerCommentsDelete(w http.ResponseWriter, r *http.Request) {
+	// Extract the article and comment IDs from the URL
+	articleID, err := uuid.Parse(r.PathValue("articleID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+	commentID, err := uuid.Parse(r.PathValue("commentID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid comment ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	// Retrieve the comment from the database using the comment ID
+	dbComment, err := cfg.db.GetComment(r.Context(), commentID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve comment", err)
+		return
+	}
+	if err != nil || dbComment.ArticleID != articleID {
+		respondWithError(w, http.StatusNotFound, "Comment not found", err)
+		return
+	}
+	// Check if the user ID from the token matches the user ID of the comment
+	if dbComment.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not delete this comment", nil)
+		return
+	}
+
+	// Delete the comment from the database, replies cascade
+	err = cfg.db.DeleteComment(r.Context(), commentID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete comment", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Respond with 204 No Content
+}
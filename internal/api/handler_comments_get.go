@@ -0,0 +1,116 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to retrieve the comments of an article, top level comments are paginated and carry their full reply thread
+func (cfg *APIConfig) handlerCommentsRetrieve(w http.ResponseWriter, r *http.Request) {
+	articleID, err := uuid.Parse(r.PathValue("articleID")) // Extract the article ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+
+	// Make sure the article exists and is visible to the user
+	_, err = cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       articleID,
+		ViewerID: viewerID(r),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Article not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+
+	pageNum, limitNum := parsePagination(r) // parse query parameters for pagination
+	offset := (pageNum - 1) * limitNum      // Calculate the offset for pagination
+
+	// Fetch the total number of top level comments
+	totalRecords, err := cfg.db.GetTotalRootCommentsCount(r.Context(), articleID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve total comments count", err)
+		return
+	}
+
+	dbRoots, err := cfg.db.GetRootComments(r.Context(), database.GetRootCommentsParams{
+		ArticleID: articleID,
+		Limit:     int32(limitNum),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve comments", err)
+		return
+	}
+
+	roots := make([]Comment, len(dbRoots))
+	rootIDs := make([]uuid.UUID, len(dbRoots))
+	for i, dbComment := range dbRoots {
+		roots[i] = newComment(dbComment.ID, dbComment.CreatedAt, dbComment.UpdatedAt, dbComment.ArticleID, dbComment.UserID, dbComment.ParentID, dbComment.Body, dbComment.Username)
+		rootIDs[i] = dbComment.ID
+	}
+
+	// Fetch every reply below the comments on this page in one go
+	replies := []Comment{}
+	if len(rootIDs) > 0 {
+		dbReplies, err := cfg.db.GetCommentReplies(r.Context(), rootIDs)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve comment replies", err)
+			return
+		}
+		for _, dbComment := range dbReplies {
+			replies = append(replies, newComment(dbComment.ID, dbComment.CreatedAt, dbComment.UpdatedAt, dbComment.ArticleID, dbComment.UserID, dbComment.ParentID, dbComment.Body, dbComment.Username))
+		}
+	}
+
+	// Create the response with metadata
+	type Metadata struct {
+		CurrentPage  int `json:"current_page"`
+		TotalPages   int `json:"total_pages"`
+		TotalRecords int `json:"total_records"`
+	}
+	type Response struct {
+		Metadata Metadata  `json:"metadata"`
+		Comments []Comment `json:"comments"`
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Metadata: Metadata{
+			CurrentPage:  pageNum,
+			TotalPages:   countPages(totalRecords, limitNum),
+			TotalRecords: int(totalRecords),
+		},
+		Comments: buildCommentThreads(roots, replies),
+	})
+}
+
+// buildCommentThreads nests replies below their parent comments, keeping the order they were given in
+func buildCommentThreads(roots, replies []Comment) []Comment {
+	children := make(map[uuid.UUID][]Comment) // replies grouped by the ID of the comment they answer
+	for _, reply := range replies {
+		if reply.ParentID == nil {
+			continue
+		}
+		children[*reply.ParentID] = append(children[*reply.ParentID], reply)
+	}
+
+	var attach func(comments []Comment) []Comment
+	attach = func(comments []Comment) []Comment {
+		for i := range comments {
+			comments[i].Replies = attach(children[comments[i].ID])
+		}
+		if comments == nil {
+			return []Comment{}
+		}
+		return comments
+	}
+	return attach(roots)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to edit the body of a comment
+func (cfg *APIConfig) handlerCommentsUpdate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	// Extract the article and comment IDs from the URL
+	articleID, err := uuid.Parse(r.PathValue("articleID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+	commentID, err := uuid.Parse(r.PathValue("commentID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid comment ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	// Retrieve the comment from the database using the comment ID
+	dbComment, err := cfg.db.GetComment(r.Context(), commentID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve comment", err)
+		return
+	}
+	if err != nil || dbComment.ArticleID != articleID {
+		respondWithError(w, http.StatusNotFound, "Comment not found", err)
+		return
+	}
+	// Check if the user ID from the token matches the user ID of the comment
+	if dbComment.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not edit this comment", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	body, err := validateComment(params.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", err)
+		return
+	}
+
+	comment, err := cfg.db.UpdateComment(r.Context(), database.UpdateCommentParams{
+		ID:   commentID,
+		Body: body,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update comment", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newComment(comment.ID, comment.CreatedAt, comment.UpdatedAt, comment.ArticleID, comment.UserID, comment.ParentID, comment.Body, dbComment.Username))
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/GitIBB/pursuit/internal/mail"
+	"github.com/google/uuid"
+)
+
+// emailVerificationDuration is how long the link in a verification mail works
+const emailVerificationDuration = 24 * time.Hour
+
+// Handler function to mail a new verification link to the address of the user, for when the first one expired
+// or got lost
+func (cfg *APIConfig) handlerEmailVerificationSend(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user", err)
+		return
+	}
+	if user.EmailVerifiedAt.Valid {
+		respondWithError(w, http.StatusConflict, "Email address is already verified", nil)
+		return
+	}
+
+	err = cfg.sendEmailVerification(r, user, user.Email)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to send verification mail", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted) // Respond with 202 Accepted, the mail is on its way
+}
+
+// sendEmailVerification mails a link to confirm an address to it, the address is the current one of the user
+// or the one they want to switch to
+func (cfg *APIConfig) sendEmailVerification(r *http.Request, user database.User, email string) error {
+	token, err := auth.MakeToken()
+	if err != nil {
+		return err
+	}
+	link, err := cfg.mailURL("/verify-email", url.Values{"token": {token}})
+	if err != nil {
+		return err
+	}
+	err = cfg.db.CreateEmailVerificationToken(r.Context(), database.CreateEmailVerificationTokenParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    user.ID,
+		Email:     email,
+		ExpiresAt: time.Now().Add(emailVerificationDuration),
+	})
+	if err != nil {
+		return err
+	}
+
+	return cfg.mailer.Send(r.Context(), mail.Message{
+		To:      email,
+		Subject: "Confirm your email address for Pursuit",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"To confirm that " + email + " is the address of your Pursuit account, open\n\n" +
+			link + "\n\n" +
+			"The link works within the next 24 hours. If you did not sign up or change your address, you can ignore this mail.\n",
+	})
+}
+
+// Handler function to confirm an address with the token from a verification mail, an address the user is
+// switching to replaces their current one
+func (cfg *APIConfig) handlerEmailConfirm(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token string `json:"token"`
+	}
+	type response struct {
+		User
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+	if params.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required", nil)
+		return
+	}
+
+	// The token is only used up together with the address change, a failed change leaves it for another attempt
+	var user database.User
+	err = cfg.inTx(r.Context(), func(q *database.Queries) error {
+		verification, err := q.UseEmailVerificationToken(r.Context(), auth.HashToken(params.Token))
+		if err != nil {
+			return err
+		}
+		user, err = q.VerifyUserEmail(r.Context(), database.VerifyUserEmailParams{
+			ID:    verification.UserID,
+			Email: verification.Email,
+		})
+		if err != nil {
+			return err
+		}
+		// Links sent for other addresses stop working, the user settled on this one
+		return q.UseAllEmailVerificationTokens(r.Context(), user.ID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Invalid or expired verification token", err)
+			return
+		}
+		if isPQError(err, pqUniqueViolation) {
+			respondWithError(w, http.StatusConflict, "Email address is already in use", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to verify email address", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response{User: newUser(user)})
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Feed formats, the extension of the feed path picks one
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+const (
+	feedSize     = 20        // number of articles in a feed, newest first
+	feedMaxAge   = 5 * 60    // seconds feed readers and proxies may cache a feed
+	feedTTL      = 60        // minutes RSS readers should wait before fetching the feed again
+	feedSiteName = "Pursuit" // title of the site feed and prefix of the other feed titles
+	feedLanguage = "en"      // language of the feeds
+)
+
+// feed holds what the RSS, Atom and JSON feeds are built from
+type feed struct {
+	Title    string
+	FeedURL  string // URL of the feed itself
+	HomeURL  string
+	Updated  time.Time // newest update of any article in the feed
+	Articles []feedArticle
+}
+
+type feedArticle struct {
+	ID        uuid.UUID
+	URL       string
+	Title     string
+	Author    string
+	Category  string
+	Summary   string
+	HTML      string // rendered body
+	Published time.Time
+	Updated   time.Time
+}
+
+// Handler function to serve the feed of the newest published articles of the site, of a category and its
+// subcategories when the path has a category slug, or of an author when the path has a user ID
+func (cfg *APIConfig) handlerFeeds(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filters := database.GetArticlesParams{
+			ViewerID: uuid.Nil, // feeds are public, they never show drafts
+			Status:   sql.NullString{String: ArticleStatusPublished, Valid: true},
+			Sort:     "newest",
+			Limit:    feedSize,
+		}
+		title := feedSiteName
+
+		if slug := r.PathValue("slug"); slug != "" {
+			category, err := cfg.db.GetCategoryBySlug(r.Context(), slug)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					respondWithError(w, http.StatusNotFound, "Category not found", err)
+					return
+				}
+				respondWithError(w, http.StatusInternalServerError, "Failed to retrieve category", err)
+				return
+			}
+			filters.CategorySlug = sql.NullString{String: category.Slug, Valid: true}
+			title = feedSiteName + ": " + category.Name
+		}
+
+		if userIDString := r.PathValue("userID"); userIDString != "" {
+			userID, err := uuid.Parse(userIDString)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+				return
+			}
+			user, err := cfg.db.GetUserByID(r.Context(), userID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					respondWithError(w, http.StatusNotFound, "User not found", err)
+					return
+				}
+				respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user", err)
+				return
+			}
+			filters.UserID = uuid.NullUUID{UUID: user.ID, Valid: true}
+			title = feedSiteName + ": articles by " + user.Username
+		}
+
+		dbArticles, err := cfg.db.GetArticles(r.Context(), filters)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve articles", err)
+			return
+		}
+
+		f := feed{
+			Title:    title,
+			FeedURL:  cfg.siteURL() + r.URL.Path,
+			HomeURL:  cfg.siteURL(),
+			Articles: make([]feedArticle, 0, len(dbArticles)),
+		}
+		for _, dbArticle := range dbArticles {
+			article, err := newArticle(database.GetArticleRow(dbArticle))
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+				return
+			}
+			published := article.CreatedAt
+			if article.PublishedAt != nil {
+				published = *article.PublishedAt
+			}
+			f.Articles = append(f.Articles, feedArticle{
+				ID:        article.ID,
+				URL:       cfg.articleURL(article.Slug),
+				Title:     article.Title,
+				Author:    article.Username,
+				Category:  article.Category,
+				Summary:   articleSummary(article.Body),
+				HTML:      renderArticleHTML(article.Body),
+				Published: published.UTC(),
+				Updated:   article.UpdatedAt.UTC(),
+			})
+			if article.UpdatedAt.After(f.Updated) {
+				f.Updated = article.UpdatedAt.UTC()
+			}
+		}
+
+		var data []byte
+		var contentType string
+		switch format {
+		case FeedFormatRSS:
+			data, err = f.rss()
+			contentType = "application/rss+xml; charset=utf-8"
+		case FeedFormatAtom:
+			data, err = f.atom()
+			contentType = "application/atom+xml; charset=utf-8"
+		default:
+			data, err = f.json()
+			contentType = "application/feed+json; charset=utf-8"
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to build feed", err)
+			return
+		}
+
+		// Let feed readers skip the download when nothing changed through If-None-Match. There is no Last-Modified,
+		// the newest update time stays put when an article is unpublished or deleted and would keep the removed
+		// article in feeds that only send If-Modified-Since.
+		hash := sha256.Sum256(data)
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(feedMaxAge))
+		w.Header().Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}
+}
+
+// rss builds an RSS 2.0 feed
+func (f feed) rss() ([]byte, error) {
+	type guid struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+	type item struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		GUID        guid   `xml:"guid"`
+		PubDate     string `xml:"pubDate"`
+		Creator     string `xml:"dc:creator"`
+		Category    string `xml:"category,omitempty"`
+		Description string `xml:"description"`
+	}
+	type atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	}
+	type channel struct {
+		Title         string   `xml:"title"`
+		Link          string   `xml:"link"`
+		Description   string   `xml:"description"`
+		Language      string   `xml:"language"`
+		LastBuildDate string   `xml:"lastBuildDate,omitempty"`
+		TTL           int      `xml:"ttl"`
+		AtomLink      atomLink `xml:"atom:link"`
+		Items         []item   `xml:"item"`
+	}
+	type rss struct {
+		XMLName  xml.Name `xml:"rss"`
+		Version  string   `xml:"version,attr"`
+		AtomNS   string   `xml:"xmlns:atom,attr"`
+		DublinNS string   `xml:"xmlns:dc,attr"`
+		Channel  channel  `xml:"channel"`
+	}
+
+	doc := rss{
+		Version:  "2.0",
+		AtomNS:   "http://www.w3.org/2005/Atom",
+		DublinNS: "http://purl.org/dc/elements/1.1/",
+		Channel: channel{
+			Title:       f.Title,
+			Link:        f.HomeURL,
+			Description: "The newest articles on " + f.Title,
+			Language:    feedLanguage,
+			TTL:         feedTTL,
+			AtomLink:    atomLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       []item{},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+	for _, article := range f.Articles {
+		doc.Channel.Items = append(doc.Channel.Items, item{
+			Title:       article.Title,
+			Link:        article.URL,
+			GUID:        guid{IsPermaLink: false, Value: article.guid()},
+			PubDate:     article.Published.Format(time.RFC1123Z),
+			Creator:     article.Author,
+			Category:    article.Category,
+			Description: article.Summary,
+		})
+	}
+	return marshalXML(doc)
+}
+
+// atom builds an Atom 1.0 feed
+func (f feed) atom() ([]byte, error) {
+	type link struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+		Type string `xml:"type,attr,omitempty"`
+	}
+	type text struct {
+		Type  string `xml:"type,attr,omitempty"`
+		Value string `xml:",chardata"`
+	}
+	type author struct {
+		Name string `xml:"name"`
+	}
+	type category struct {
+		Term string `xml:"term,attr"`
+	}
+	type entry struct {
+		Title     string    `xml:"title"`
+		ID        string    `xml:"id"`
+		Link      link      `xml:"link"`
+		Published string    `xml:"published"`
+		Updated   string    `xml:"updated"`
+		Author    author    `xml:"author"`
+		Category  *category `xml:"category"`
+		Summary   text      `xml:"summary"`
+		Content   text      `xml:"content"`
+	}
+	type atomFeed struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string   `xml:"title"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Links   []link   `xml:"link"`
+		Entries []entry  `xml:"entry"`
+	}
+
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0).UTC() // Atom requires an updated time, even for an empty feed
+	}
+	doc := atomFeed{
+		Title:   f.Title,
+		ID:      f.FeedURL,
+		Updated: updated.Format(time.RFC3339),
+		Links: []link{
+			{Href: f.FeedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.HomeURL, Rel: "alternate"},
+		},
+		Entries: []entry{},
+	}
+	for _, article := range f.Articles {
+		e := entry{
+			Title:     article.Title,
+			ID:        article.guid(),
+			Link:      link{Href: article.URL, Rel: "alternate", Type: "text/html"},
+			Published: article.Published.Format(time.RFC3339),
+			Updated:   article.Updated.Format(time.RFC3339),
+			Author:    author{Name: article.Author},
+			Summary:   text{Value: article.Summary},
+			Content:   text{Type: "html", Value: article.HTML},
+		}
+		if article.Category != "" {
+			e.Category = &category{Term: article.Category}
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+	return marshalXML(doc)
+}
+
+// json builds a JSON Feed 1.1
+func (f feed) json() ([]byte, error) {
+	type author struct {
+		Name string `json:"name"`
+	}
+	type item struct {
+		ID            string    `json:"id"`
+		URL           string    `json:"url"`
+		Title         string    `json:"title"`
+		Summary       string    `json:"summary"`
+		ContentHTML   string    `json:"content_html"`
+		DatePublished time.Time `json:"date_published"`
+		DateModified  time.Time `json:"date_modified"`
+		Authors       []author  `json:"authors"`
+		Tags          []string  `json:"tags,omitempty"`
+	}
+	type jsonFeed struct {
+		Version     string `json:"version"`
+		Title       string `json:"title"`
+		HomePageURL string `json:"home_page_url"`
+		FeedURL     string `json:"feed_url"`
+		Language    string `json:"language"`
+		Items       []item `json:"items"`
+	}
+
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.HomeURL,
+		FeedURL:     f.FeedURL,
+		Language:    feedLanguage,
+		Items:       []item{},
+	}
+	for _, article := range f.Articles {
+		i := item{
+			ID:            article.guid(),
+			URL:           article.URL,
+			Title:         article.Title,
+			Summary:       article.Summary,
+			ContentHTML:   article.HTML,
+			DatePublished: article.Published,
+			DateModified:  article.Updated,
+			Authors:       []author{{Name: article.Author}},
+		}
+		if article.Category != "" {
+			i.Tags = []string{article.Category}
+		}
+		doc.Items = append(doc.Items, i)
+	}
+	return json.Marshal(doc)
+}
+
+// guid identifies the article in every feed format, it does not change when the title and with it the URL does
+func (article feedArticle) guid() string {
+	return "urn:uuid:" + article.ID.String()
+}
+
+// marshalXML encodes an XML document with the XML declaration in front
+func marshalXML(doc any) ([]byte, error) {
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
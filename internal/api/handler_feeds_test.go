@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFeeds(t *testing.T) {
+	tests := []struct {
+		name            string
+		format          string
+		wantContentType string
+	}{
+		{name: "RSS", format: FeedFormatRSS, wantContentType: "application/rss+xml; charset=utf-8"},
+		{name: "Atom", format: FeedFormatAtom, wantContentType: "application/atom+xml; charset=utf-8"},
+		{name: "JSON Feed", format: FeedFormatJSON, wantContentType: "application/feed+json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := newCountingConfig(3)
+			handler := cfg.handlerFeeds(tt.format)
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/feeds/articles."+tt.format, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+
+			var valid bool
+			if tt.format == FeedFormatJSON {
+				valid = json.Valid(rec.Body.Bytes())
+			} else {
+				valid = xml.Unmarshal(rec.Body.Bytes(), new(struct{})) == nil
+			}
+			if !valid {
+				t.Errorf("feed does not parse:\n%s", rec.Body.String())
+			}
+
+			// Asking again with the ETag of the first response must not send the feed again, an update time
+			// cannot tell that an article was removed, so it does not count
+			etag := rec.Header().Get("ETag")
+			if etag == "" || rec.Header().Get("Last-Modified") != "" {
+				t.Fatalf("ETag = %q, Last-Modified = %q, want only an ETag", etag, rec.Header().Get("Last-Modified"))
+			}
+			for header, want := range map[string]int{"If-None-Match": http.StatusNotModified, "If-Modified-Since": http.StatusOK} {
+				value := etag
+				if header == "If-Modified-Since" {
+					value = time.Now().UTC().Format(http.TimeFormat)
+				}
+				req := httptest.NewRequest(http.MethodGet, "/feeds/articles."+tt.format, nil)
+				req.Header.Set(header, value)
+				rec := httptest.NewRecorder()
+				handler(rec, req)
+				if rec.Code != want {
+					t.Errorf("%s: status = %d, want %d", header, rec.Code, want)
+				}
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/GitIBB/pursuit/internal/auth"
-	"github.com/GitIBB/pursuit/internal/database"
 )
 
 // handlerLogin handles user login requests
@@ -18,7 +17,7 @@ func (cfg *APIConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 	type response struct { // struct to hold the response data
 		User
 		Token        string `json:"token"`
-		RefreshToken string `json:"refresh_token"`
+		RefreshToken string `json:"refresh_token,omitempty"` // only for clients that are not browsers, browsers get a cookie
 	}
 
 	decoder := json.NewDecoder(r.Body) // create a new JSON decoder for request body
@@ -41,8 +40,15 @@ func (cfg *APIConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	session, refreshToken, err := cfg.startSession(r, user.ID) // Start a new session with its first refresh token, only its hash is saved
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create refresh token", err)
+		return
+	}
+
 	accessToken, err := auth.MakeJWT( // Create a new JWT token for user
 		user.ID,
+		session.ID,
 		cfg.jwtSecret,
 		time.Hour,
 	)
@@ -51,43 +57,15 @@ func (cfg *APIConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	refreshToken, err := auth.MakeRefreshToken() // Create a new refresh token for the user
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to create refresh token", err)
-		return
-	}
-	_, err = cfg.db.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Hour * 24 * 60), // set the expiration time for the refresh token to 60 days
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to save refresh token in database", err)
-		return
-	}
-
 	// check if the client is a browser
 	if IsBrowser(r) {
-		// set a cookie for browser clients
-		http.SetCookie(w, &http.Cookie{
-			Name:     "auth-token",
-			Value:    accessToken,
-			HttpOnly: true,
-			Path:     "/",
-			Expires:  time.Now().Add(time.Hour), // Set the expiration time for the cookie to match the token expiration
-			Secure:   true,                      // set to true if using HTTPS (during production).
-			SameSite: http.SameSiteNoneMode,     // allow cross-site usage
-		})
+		// set cookies for browser clients, the refresh token is kept out of reach of scripts
+		setSessionCookies(w, accessToken, refreshToken)
+		refreshToken = ""
 	}
 
 	respondWithJSON(w, http.StatusOK, response{ // create a new response instance containing the user data and token
-		User: User{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Username:  user.Username,
-			Email:     user.Email,
-		},
+		User:         newUser(user),
 		Token:        accessToken,
 		RefreshToken: refreshToken,
 	})
@@ -2,20 +2,59 @@ package api
 
 import (
 	"net/http"
-	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
 )
 
+// handlerLogout ends the session the request was made with. It does not need a valid access token, so clients whose
+// access token expired can still log out: the session is the one of the access token when the request has a valid
+// one, and the one of the refresh token otherwise. A valid access token is denied by its jti for the rest of its
+// lifetime, and revoking the refresh tokens of the session ends the other access tokens issued for it.
 func (cfg *APIConfig) handlerLogout(w http.ResponseWriter, r *http.Request) {
-	// clear auth-token cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "auth-token",
-		Value:    "",
-		Path:     "/",
-		Expires:  time.Unix(0, 0), // expire immediately
-		HttpOnly: true,
-		Secure:   true, // Set to true in production with HTTPS
-		SameSite: http.SameSiteNoneMode,
-	})
+	var accessToken auth.AccessToken
+	token, err := getRequestToken(r)
+	if err == nil {
+		accessToken, err = auth.ParseJWT(token, cfg.jwtSecret)
+	}
+
+	if err == nil && accessToken.SessionID != uuid.Nil {
+		// revoke the refresh tokens of the session the access token was issued for, and deny the token itself
+		err = cfg.inTx(r.Context(), func(q *database.Queries) error {
+			_, err := q.RevokeSession(r.Context(), database.RevokeSessionParams{
+				SessionID: accessToken.SessionID,
+				UserID:    accessToken.UserID,
+			})
+			if err != nil || accessToken.ID == "" {
+				return err
+			}
+			return q.DenyAccessToken(r.Context(), database.DenyAccessTokenParams{
+				Jti:       accessToken.ID,
+				ExpiresAt: accessToken.ExpiresAt,
+			})
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to revoke session", err)
+			return
+		}
+	} else {
+		// without a valid access token, revoke the session of the refresh token from the cookie or Authorization header
+		refreshToken, _, err := getRefreshToken(r)
+		if err != nil {
+			clearSessionCookies(w)
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing token", err)
+			return
+		}
+		_, err = cfg.db.RevokeRefreshTokenSession(r.Context(), auth.HashToken(refreshToken))
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to revoke session", err)
+			return
+		}
+	}
+
+	// clear the auth-token and refresh-token cookies
+	clearSessionCookies(w)
 
 	// respond with success message
 	w.WriteHeader(http.StatusOK)
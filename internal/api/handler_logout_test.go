@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/google/uuid"
+)
+
+func TestLogoutEndsAccessToken(t *testing.T) {
+	cfg, connector := newCountingConfig(0)
+	userID := uuid.New()
+	loggedOut, _ := auth.MakeJWT(userID, uuid.New(), "secret", time.Hour)
+	other, _ := auth.MakeJWT(userID, uuid.New(), "secret", time.Hour)
+
+	protected := cfg.middlewareAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	request := func(handler http.Handler, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request(http.HandlerFunc(cfg.handlerLogout), "/api/logout", loggedOut)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("logout status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := connector.queries.Load(); got != 2 {
+		t.Errorf("logout queries = %d, want 2 to revoke the session and deny the token", got)
+	}
+	if parsed, _ := auth.ParseJWT(loggedOut, "secret"); !isDenied(connector, parsed.ID) {
+		t.Errorf("jti %q of the logged out token is not denied", parsed.ID)
+	}
+	if !sessionCookiesCleared(rec) {
+		t.Errorf("cookies = %v, want auth-token and %s cleared", rec.Result().Cookies(), refreshTokenCookie)
+	}
+
+	if rec := request(protected, "/api/me", loggedOut); rec.Code != http.StatusUnauthorized {
+		t.Errorf("logged out token: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := request(protected, "/api/me", other); rec.Code != http.StatusNoContent {
+		t.Errorf("token of another session: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestLogoutWithExpiredAccessToken(t *testing.T) {
+	cfg, connector := newCountingConfig(0)
+	expired, _ := auth.MakeJWT(uuid.New(), uuid.New(), "secret", -time.Minute)
+
+	// Browsers still send the refresh token cookie once the access token cookie expired
+	req := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	req.AddCookie(&http.Cookie{Name: "auth-token", Value: expired})
+	req.AddCookie(&http.Cookie{Name: refreshTokenCookie, Value: "refresh"})
+	rec := httptest.NewRecorder()
+	cfg.handlerLogout(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := connector.queries.Load(); got != 1 {
+		t.Errorf("queries = %d, want 1 to revoke the session of the refresh token", got)
+	}
+	if !sessionCookiesCleared(rec) {
+		t.Errorf("cookies = %v, want auth-token and %s cleared", rec.Result().Cookies(), refreshTokenCookie)
+	}
+
+	// Without any token there is no session to end
+	rec = httptest.NewRecorder()
+	cfg.handlerLogout(rec, httptest.NewRequest(http.MethodPost, "/api/logout", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without tokens: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+// sessionCookiesCleared reports whether the response expires the auth-token and refresh-token cookies
+func sessionCookiesCleared(rec *httptest.ResponseRecorder) bool {
+	cleared := map[string]bool{}
+	for _, cookie := range rec.Result().Cookies() {
+		cleared[cookie.Name] = cookie.Value == "" && cookie.Expires.Before(time.Now())
+	}
+	return cleared["auth-token"] && cleared[refreshTokenCookie]
+}
+
+// isDenied reports whether the access token with the jti was denied
+func isDenied(connector *countingConnector, jti string) bool {
+	_, denied := connector.deniedTokens.Load(jti)
+	return denied
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/GitIBB/pursuit/internal/mail"
+)
+
+// passwordResetDuration is how long the link in a password reset mail works
+const passwordResetDuration = time.Hour
+
+// Handler function to mail a password reset link to the address of an account. The response is the same whether
+// an account has the address or not, so the endpoint cannot be used to find out who is registered.
+func (cfg *APIConfig) handlerPasswordForgot(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email string `json:"email"`
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	// Look the address up and send the mail after responding, so neither the response nor the time it takes
+	// depends on whether an account has the address
+	ctx := context.WithoutCancel(r.Context())
+	cfg.background.Add(1)
+	go func() {
+		defer cfg.background.Done()
+		if err := cfg.sendPasswordReset(ctx, params.Email); err != nil {
+			log.Printf("Failed to send password reset mail: %v", err)
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted) // Respond with 202 Accepted, the mail is on its way if the account exists
+}
+
+// sendPasswordReset mails a reset link to the account with the address, addresses without one are ignored
+func (cfg *APIConfig) sendPasswordReset(ctx context.Context, email string) error {
+	user, err := cfg.db.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := auth.MakeToken()
+	if err != nil {
+		return err
+	}
+	link, err := cfg.mailURL("/reset-password", url.Values{"token": {token}})
+	if err != nil {
+		return err
+	}
+	err = cfg.db.CreatePasswordResetToken(ctx, database.CreatePasswordResetTokenParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(passwordResetDuration),
+	})
+	if err != nil {
+		return err
+	}
+
+	return cfg.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Pursuit password",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Someone asked to reset the password of your Pursuit account. To choose a new password, open\n\n" +
+			link + "\n\n" +
+			"The link works once, within the next hour. If you did not ask for it, you can ignore this mail.\n",
+	})
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GitIBB/pursuit/internal/mail"
+	"github.com/GitIBB/pursuit/internal/mail/mailtest"
+)
+
+func TestPasswordForgotSendsResetLink(t *testing.T) {
+	cfg, _ := newCountingConfig(0)
+	cfg.SetBaseURL("https://pursuit.example.com")
+	mailer := &mailtest.MemorySender{}
+	cfg.SetMailer(mailer)
+
+	rec := httptest.NewRecorder()
+	cfg.handlerPasswordForgot(rec, httptest.NewRequest(http.MethodPost, "/api/password/forgot", strings.NewReader(`{"email": "reader@example.com"}`)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+
+	cfg.background.Wait()
+	messages := mailer.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("sent %d mails, want 1", len(messages))
+	}
+	if messages[0].To != "reader@example.com" {
+		t.Errorf("mail to %q, want reader@example.com", messages[0].To)
+	}
+	if !strings.Contains(messages[0].Body, "https://pursuit.example.com/reset-password?token=") {
+		t.Errorf("mail has no reset link:\n%s", messages[0].Body)
+	}
+}
+
+func TestPasswordForgotWithoutBaseURL(t *testing.T) {
+	cfg, _ := newCountingConfig(0)
+	mailer := &mailtest.MemorySender{}
+	cfg.SetMailer(mailer)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/password/forgot", strings.NewReader(`{"email": "reader@example.com"}`))
+	req.Host = "attacker.example"
+	rec := httptest.NewRecorder()
+	cfg.handlerPasswordForgot(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	cfg.background.Wait()
+	if messages := mailer.Messages(); len(messages) != 0 {
+		t.Fatalf("sent %d mails, want none:\n%s", len(messages), messages[0].Body)
+	}
+}
+
+func TestPasswordForgotHidesSendFailures(t *testing.T) {
+	cfg, _ := newCountingConfig(0)
+	cfg.SetBaseURL("https://pursuit.example.com")
+	cfg.SetMailer(failingSender{})
+
+	rec := httptest.NewRecorder()
+	cfg.handlerPasswordForgot(rec, httptest.NewRequest(http.MethodPost, "/api/password/forgot", strings.NewReader(`{"email": "reader@example.com"}`)))
+	cfg.background.Wait()
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d, the same as for unknown addresses", rec.Code, http.StatusAccepted)
+	}
+}
+
+// failingSender fails to deliver every message
+type failingSender struct{}
+
+func (failingSender) Send(context.Context, mail.Message) error {
+	return errors.New("mail server unavailable")
+}
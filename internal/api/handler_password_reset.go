@@ -0,0 +1,72 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+)
+
+// Handler function to set a new password with the token from a password reset mail. Every session of the user
+// is logged out afterwards, whoever knew the old password can no longer use or refresh their tokens.
+func (cfg *APIConfig) handlerPasswordReset(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+	if params.Token == "" || params.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Token and password are required", nil)
+		return
+	}
+
+	// Hash the password before the token is used up, so a failure here leaves the link working
+	hashedPassword, err := auth.HashPassword(params.Password)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to hash password", err)
+		return
+	}
+
+	// The token is only used up together with the password change and the logout, so a failure leaves the link
+	// working and never changes the password without ending the sessions
+	err = cfg.inTx(r.Context(), func(q *database.Queries) error {
+		resetToken, err := q.UsePasswordResetToken(r.Context(), auth.HashToken(params.Token))
+		if err != nil {
+			return err
+		}
+		err = q.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+			ID:             resetToken.UserID,
+			HashedPassword: hashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+		// Other reset links sent before this one stop working as well
+		err = q.UseAllPasswordResetTokens(r.Context(), resetToken.UserID)
+		if err != nil {
+			return err
+		}
+		_, err = q.RevokeAllSessions(r.Context(), resetToken.UserID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Invalid or expired reset token", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to reset password", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Respond with 204 No Content
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/google/uuid"
+)
+
+func TestPasswordResetEndsEverySession(t *testing.T) {
+	cfg, connector := newCountingConfig(0)
+	userID, otherUserID := uuid.New(), uuid.New()
+	connector.resetTokens.Store(auth.HashToken("reset"), &cannedResetToken{userID: userID, expiresAt: time.Now().Add(time.Hour)})
+
+	// two sessions of the user, and one of someone else
+	tokens := map[string]string{}
+	for name, owner := range map[string]uuid.UUID{"phone": userID, "laptop": userID, "other user": otherUserID} {
+		sessionID := uuid.New()
+		connector.sessionUsers.Store(sessionID.String(), owner.String())
+		token, _ := auth.MakeJWT(owner, sessionID, "secret", time.Hour)
+		tokens[name] = token
+	}
+	protected := cfg.middlewareAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	status := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	rec := postPasswordReset(cfg, "reset")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body)
+	}
+	for _, name := range []string{"phone", "laptop"} {
+		if got := status(tokens[name]); got != http.StatusUnauthorized {
+			t.Errorf("%s session: status = %d, want %d", name, got, http.StatusUnauthorized)
+		}
+	}
+	if got := status(tokens["other user"]); got != http.StatusNoContent {
+		t.Errorf("session of another user: status = %d, want %d", got, http.StatusNoContent)
+	}
+
+	// The link works once
+	if rec := postPasswordReset(cfg, "reset"); rec.Code != http.StatusBadRequest {
+		t.Errorf("used token: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPasswordResetRejectsToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token *cannedResetToken
+	}{
+		{
+			name:  "Used token",
+			token: &cannedResetToken{userID: uuid.New(), expiresAt: time.Now().Add(time.Hour), used: true},
+		},
+		{
+			name:  "Expired token",
+			token: &cannedResetToken{userID: uuid.New(), expiresAt: time.Now().Add(-time.Minute)},
+		},
+		{
+			name: "Unknown token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, connector := newCountingConfig(0)
+			if tt.token != nil {
+				connector.resetTokens.Store(auth.HashToken("reset"), tt.token)
+			}
+
+			rec := postPasswordReset(cfg, "reset")
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			// Nothing else runs once the token is refused
+			if got := connector.queries.Load(); got != 1 {
+				t.Errorf("queries = %d, want 1", got)
+			}
+		})
+	}
+}
+
+// postPasswordReset sets a new password with the reset token
+func postPasswordReset(cfg *APIConfig, token string) *httptest.ResponseRecorder {
+	body := `{"token": "` + token + `", "password": "correct horse battery staple"}`
+	rec := httptest.NewRecorder()
+	cfg.handlerPasswordReset(rec, httptest.NewRequest(http.MethodPost, "/api/password/reset", strings.NewReader(body)))
+	return rec
+}
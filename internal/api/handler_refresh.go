@@ -1,31 +1,77 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
 )
 
+// refreshTokenDuration is how long a refresh token stays valid, every refresh hands out a new one for the full period
+const refreshTokenDuration = 60 * 24 * time.Hour
+
+// handlerRefresh exchanges a refresh token for a new access token and a new refresh token, the old refresh token
+// stops working. Presenting a refresh token that was already exchanged means a copy of it is around, so the whole
+// session the token belongs to is revoked and the user has to log in again.
 func (cfg *APIConfig) handlerRefresh(w http.ResponseWriter, r *http.Request) {
 	type response struct {
-		Token string `json:"token"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token,omitempty"` // only when the old one was not sent in a cookie
 	}
 
-	refreshToken, err := auth.GetBearerToken(r.Header)
+	refreshToken, fromCookie, err := getRefreshToken(r)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "could not locate token", err)
 		return
 	}
 
-	user, err := cfg.db.GetUserFromRefreshToken(r.Context(), refreshToken)
+	newRefreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create refresh token", err)
+		return
+	}
+
+	rotated, err := cfg.db.RotateRefreshToken(r.Context(), database.RotateRefreshTokenParams{
+		NewTokenHash: auth.HashToken(newRefreshToken),
+		TokenHash:    auth.HashToken(refreshToken),
+		ExpiresAt:    time.Now().Add(refreshTokenDuration),
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Failed to rotate refresh token", err)
+			return
+		}
+
+		// The token is unknown, expired or revoked, check whether it was revoked because it has been exchanged before
+		revoked, err := cfg.db.RevokeReusedRefreshTokenFamily(r.Context(), auth.HashToken(refreshToken))
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to revoke refresh tokens", err)
+			return
+		}
+		if revoked > 0 {
+			log.Printf("[REFRESH TOKEN REUSE] %s %s from %s - revoked %d tokens", r.Method, r.URL.Path, r.RemoteAddr, revoked)
+		}
+		respondWithError(w, http.StatusUnauthorized, "Invalid refresh token", nil)
+		return
+	}
+
+	err = cfg.db.UpdateSessionActivity(r.Context(), database.UpdateSessionActivityParams{
+		ID:        rotated.SessionID,
+		UserAgent: clientUserAgent(r),
+		Ip:        clientIP(r),
+	})
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Could not get user for refresh token", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update session", err)
 		return
 	}
 
 	accessToken, err := auth.MakeJWT(
-		user.ID,
+		rotated.UserID,
+		rotated.SessionID,
 		cfg.jwtSecret,
 		time.Hour,
 	)
@@ -34,19 +80,26 @@ func (cfg *APIConfig) handlerRefresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Browser clients sent their refresh token in a cookie, the new one goes back the same way
+	if fromCookie {
+		setSessionCookies(w, accessToken, newRefreshToken)
+		newRefreshToken = ""
+	}
+
 	respondWithJSON(w, http.StatusOK, response{
-		Token: accessToken,
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
 	})
 }
 
 func (cfg *APIConfig) handlerRevoke(w http.ResponseWriter, r *http.Request) {
-	refreshToken, err := auth.GetBearerToken(r.Header)
+	refreshToken, _, err := getRefreshToken(r)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "could not locate token", err)
 		return
 	}
 
-	_, err = cfg.db.RevokeRefreshToken(r.Context(), refreshToken)
+	_, err = cfg.db.RevokeRefreshToken(r.Context(), auth.HashToken(refreshToken))
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Could not revoke session", err)
 		return
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Handler function to show what changed between two revisions of an article
+func (cfg *APIConfig) handlerRevisionsDiff(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		From   int32         `json:"from"`
+		To     int32         `json:"to"`
+		Fields []FieldChange `json:"fields"`
+		Blocks []BlockChange `json:"blocks"`
+	}
+
+	articleID, err := uuid.Parse(r.PathValue("articleID")) // Extract the article ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+	fromNumber, err := parseRevisionNumber(r.URL.Query().Get("from")) // Get the from query parameter from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid from revision", err)
+		return
+	}
+	toNumber, err := parseRevisionNumber(r.URL.Query().Get("to")) // Get the to query parameter from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid to revision", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	dbFrom, ok := cfg.getOwnedRevision(w, r, articleID, fromNumber, userID)
+	if !ok {
+		return
+	}
+	dbTo, ok := cfg.getOwnedRevision(w, r, articleID, toNumber, userID)
+	if !ok {
+		return
+	}
+
+	from, err := newArticleRevision(dbFrom)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal revision body", err)
+		return
+	}
+	to, err := newArticleRevision(dbTo)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal revision body", err)
+		return
+	}
+
+	// Compare the plain fields first, then the blocks of the body
+	fields := []FieldChange{}
+	for _, field := range []FieldChange{
+		{Field: "title", From: from.Title, To: to.Title},
+		{Field: "category_id", From: from.CategoryID.String(), To: to.CategoryID.String()},
+		{Field: "image_url", From: from.ImageUrl, To: to.ImageUrl},
+		{Field: "status", From: from.Status, To: to.Status},
+	} {
+		if field.From != field.To {
+			fields = append(fields, field)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		From:   from.Revision,
+		To:     to.Revision,
+		Fields: fields,
+		Blocks: diffArticleBodies(from.Body, to.Body),
+	})
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+type ArticleRevision struct { // struct to hold a snapshot of an article
+	ID         uuid.UUID   `json:"id"`
+	ArticleID  uuid.UUID   `json:"article_id"`
+	Revision   int32       `json:"revision"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Event      string      `json:"event"` // created, updated or deleted
+	UserID     uuid.UUID   `json:"user_id"`
+	CategoryID uuid.UUID   `json:"category_id"`
+	Title      string      `json:"title"`
+	Body       ArticleBody `json:"body"`
+	ImageUrl   string      `json:"image_url"`
+	Status     string      `json:"status"`
+}
+
+// Handler function to list every revision of an article, newest first
+func (cfg *APIConfig) handlerRevisionsRetrieve(w http.ResponseWriter, r *http.Request) {
+	articleID, err := uuid.Parse(r.PathValue("articleID")) // Extract the article ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	dbRevisions, err := cfg.db.GetArticleRevisions(r.Context(), articleID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve revisions", err)
+		return
+	}
+	if len(dbRevisions) == 0 {
+		respondWithError(w, http.StatusNotFound, "Article not found", nil)
+		return
+	}
+	// Revisions are only visible to the author of the article, also after the article was deleted
+	if dbRevisions[0].UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not view the revisions of this article", nil)
+		return
+	}
+
+	revisions := make([]ArticleRevision, len(dbRevisions))
+	for i, dbRevision := range dbRevisions {
+		revisions[i], err = newArticleRevision(dbRevision)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal revision body", err)
+			return
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, revisions)
+}
+
+// Handler function to retrieve a single revision of an article
+func (cfg *APIConfig) handlerRevisionsGet(w http.ResponseWriter, r *http.Request) {
+	articleID, err := uuid.Parse(r.PathValue("articleID")) // Extract the article ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+	revisionNumber, err := parseRevisionNumber(r.PathValue("revision")) // Extract the revision number from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid revision number", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	dbRevision, ok := cfg.getOwnedRevision(w, r, articleID, revisionNumber, userID)
+	if !ok {
+		return
+	}
+
+	revision, err := newArticleRevision(dbRevision)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal revision body", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, revision)
+}
+
+// getOwnedRevision fetches a revision and checks that it belongs to an article written by the user,
+// responding with an error and returning false otherwise
+func (cfg *APIConfig) getOwnedRevision(w http.ResponseWriter, r *http.Request, articleID uuid.UUID, revisionNumber int32, userID uuid.UUID) (database.ArticleRevision, bool) {
+	dbRevision, err := cfg.db.GetArticleRevision(r.Context(), database.GetArticleRevisionParams{
+		ArticleID:      articleID,
+		RevisionNumber: revisionNumber,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Revision not found", err)
+			return dbRevision, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve revision", err)
+		return dbRevision, false
+	}
+	if dbRevision.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not view the revisions of this article", nil)
+		return dbRevision, false
+	}
+	return dbRevision, true
+}
+
+// newArticleRevision converts a database revision into the API representation
+func newArticleRevision(dbRevision database.ArticleRevision) (ArticleRevision, error) {
+	body, err := decodeArticleBody(dbRevision.Body)
+	if err != nil {
+		return ArticleRevision{}, err
+	}
+	return ArticleRevision{
+		ID:         dbRevision.ID,
+		ArticleID:  dbRevision.ArticleID,
+		Revision:   dbRevision.RevisionNumber,
+		CreatedAt:  dbRevision.CreatedAt,
+		Event:      dbRevision.Event,
+		UserID:     dbRevision.UserID,
+		CategoryID: dbRevision.CategoryID,
+		Title:      dbRevision.Title,
+		Body:       body,
+		ImageUrl:   dbRevision.ImageUrl.String,
+		Status:     dbRevision.Status,
+	}, nil
+}
+
+func parseRevisionNumber(value string) (int32, error) { // Function to parse a revision number from the URL or query
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if number < 1 {
+		return 0, errors.New("revision numbers start at 1")
+	}
+	return int32(number), nil
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to make an older revision the current version of an article.
+// Deleted articles are brought back as a draft with their original ID.
+func (cfg *APIConfig) handlerRevisionsRestore(w http.ResponseWriter, r *http.Request) {
+	articleID, err := uuid.Parse(r.PathValue("articleID")) // Extract the article ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+	revisionNumber, err := parseRevisionNumber(r.PathValue("revision")) // Extract the revision number from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid revision number", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	revision, ok := cfg.getOwnedRevision(w, r, articleID, revisionNumber, userID)
+	if !ok {
+		return
+	}
+
+	// The restored title decides the slug, like it does when the title is edited
+	slug, err := cfg.nextArticleSlug(r.Context(), revision.Title, articleID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate article slug", err)
+		return
+	}
+
+	// Write the revision back to the article, this records a new revision in turn
+	article, err := cfg.db.RestoreArticleRevision(r.Context(), database.RestoreArticleRevisionParams{
+		ArticleID:      articleID,
+		RevisionNumber: revisionNumber,
+		Slug:           slug,
+	})
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			respondWithError(w, http.StatusConflict, "Another article took the slug for this title, please try again", err)
+			return
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			respondWithError(w, http.StatusConflict, "The category of this revision no longer exists", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to restore revision", err)
+		return
+	}
+
+	// Read the restored article back with its author, category and tags
+	dbArticle, err := cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       article.ID,
+		ViewerID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+
+	restored, err := newArticle(dbArticle)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, restored)
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+type SearchResult struct { // struct to hold a single search hit
+	Article
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"` // escaped title with matches wrapped in <mark> tags
+	Snippet        string  `json:"snippet"`         // escaped plain text fragments of the content with matches wrapped in <mark> tags
+}
+
+// ts_headline wraps matches in these private use characters rather than in <mark> tags, so that highlightHTML can
+// escape the text around them first and only the <mark> tags it writes itself end up as markup
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// Handler function to search articles by their title, headers and content
+func (cfg *APIConfig) handlerSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q")) // Get the search terms from the URL
+	if query == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing search query", nil)
+		return
+	}
+
+	categoryID, categoryName := parseCategoryParam(r.URL.Query().Get("category")) // category ID or name
+	authorID, err := parseUUIDParam(r.URL.Query().Get("author"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid author ID", err)
+		return
+	}
+	createdFrom, err := parseDateParam(r.URL.Query().Get("from"), false)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid from date", err)
+		return
+	}
+	createdTo, err := parseDateParam(r.URL.Query().Get("to"), true)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid to date", err)
+		return
+	}
+
+	pageNum, limitNum := parsePagination(r) // parse query parameters for pagination
+	offset := (pageNum - 1) * limitNum      // Calculate the offset for pagination
+	viewer := viewerID(r)                   // authors also find their own drafts
+
+	totalRecords, err := cfg.db.GetTotalSearchResultsCount(r.Context(), database.GetTotalSearchResultsCountParams{
+		Query:        query,
+		ViewerID:     viewer,
+		CategoryID:   categoryID,
+		CategoryName: categoryName,
+		AuthorID:     authorID,
+		CreatedFrom:  createdFrom,
+		CreatedTo:    createdTo,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve total search results count", err)
+		return
+	}
+
+	dbResults, err := cfg.db.SearchArticles(r.Context(), database.SearchArticlesParams{
+		Query:        query,
+		ViewerID:     viewer,
+		CategoryID:   categoryID,
+		CategoryName: categoryName,
+		AuthorID:     authorID,
+		CreatedFrom:  createdFrom,
+		CreatedTo:    createdTo,
+		Limit:        int32(limitNum),
+		Offset:       int32(offset),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to search articles", err)
+		return
+	}
+
+	results := []SearchResult{}
+	for _, dbResult := range dbResults {
+		// The search rows carry the same article columns as GetArticle, followed by the search specific ones
+		article, err := newArticle(articleRowOf(dbResult))
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to unmarshal article body", err)
+			return
+		}
+
+		results = append(results, SearchResult{
+			Article:        article,
+			Rank:           dbResult.Rank,
+			TitleHighlight: highlightHTML(dbResult.TitleHighlight, false),
+			Snippet:        highlightHTML(dbResult.Snippet, true), // the content is stored as HTML, its entities are decoded first
+		})
+	}
+
+	// Create the response with metadata
+	type Metadata struct {
+		CurrentPage  int `json:"current_page"`
+		TotalPages   int `json:"total_pages"`
+		TotalRecords int `json:"total_records"`
+	}
+	type Response struct {
+		Metadata Metadata       `json:"metadata"`
+		Results  []SearchResult `json:"results"`
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Metadata: Metadata{
+			CurrentPage:  pageNum,
+			TotalPages:   countPages(totalRecords, limitNum),
+			TotalRecords: int(totalRecords),
+		},
+		Results: results,
+	})
+}
+
+// highlightHTML escapes a ts_headline result and turns the sentinels around its matches into <mark> tags. With
+// unescape set the text is decoded from HTML first, so entities in stored article text are not escaped twice.
+func highlightHTML(headline string, unescape bool) string {
+	var b strings.Builder
+	open := false
+	for headline != "" {
+		i := strings.IndexAny(headline, highlightStart+highlightStop)
+		if i < 0 {
+			i = len(headline)
+		}
+		text := headline[:i]
+		if unescape {
+			text = html.UnescapeString(text)
+		}
+		b.WriteString(html.EscapeString(text))
+		if i == len(headline) {
+			break
+		}
+
+		// Only open and close marks in turn, so stray sentinels in the text cannot unbalance the markup
+		sentinel := headline[i : i+len(highlightStart)]
+		if sentinel == highlightStart && !open {
+			b.WriteString("<mark>")
+			open = true
+		} else if sentinel == highlightStop && open {
+			b.WriteString("</mark>")
+			open = false
+		}
+		headline = headline[i+len(highlightStart):]
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
+
+// parseCategoryParam treats the value as a category ID when it is a UUID and as a category name otherwise
+func parseCategoryParam(value string) (uuid.NullUUID, sql.NullString) {
+	if value == "" {
+		return uuid.NullUUID{}, sql.NullString{}
+	}
+	if id, err := uuid.Parse(value); err == nil {
+		return uuid.NullUUID{UUID: id, Valid: true}, sql.NullString{}
+	}
+	return uuid.NullUUID{}, sql.NullString{String: value, Valid: true}
+}
+
+// parseUUIDParam parses an optional UUID query parameter
+func parseUUIDParam(value string) (uuid.NullUUID, error) {
+	if value == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
+
+// parseDateParam parses an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter.
+// With endOfDay set a plain date covers the whole day, so it can be used as an exclusive upper bound.
+func parseDateParam(value string, endOfDay bool) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return sql.NullTime{Time: t.UTC(), Valid: true}, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return sql.NullTime{}, errors.New("dates must be formatted as YYYY-MM-DD or RFC 3339")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
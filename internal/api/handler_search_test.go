@@ -0,0 +1,42 @@
+package api
+
+import "testing"
+
+func TestHighlightHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		unescape bool
+		want     string
+	}{
+		{
+			name:     "Markup in a title is escaped",
+			headline: `<img src=x onerror=alert(1)> Learning ` + highlightStart + "Go" + highlightStop,
+			want:     `&lt;img src=x onerror=alert(1)&gt; Learning <mark>Go</mark>`,
+		},
+		{
+			name:     "Titles are not decoded",
+			headline: "Tom &amp; " + highlightStart + "Jerry" + highlightStop,
+			want:     "Tom &amp;amp; <mark>Jerry</mark>",
+		},
+		{
+			name:     "Entities in article text are escaped once",
+			headline: "a &lt;b&gt; tag in " + highlightStart + "Go" + highlightStop + " &amp; more",
+			unescape: true,
+			want:     "a &lt;b&gt; tag in <mark>Go</mark> &amp; more",
+		},
+		{
+			name:     "Stray sentinels leave balanced marks",
+			headline: highlightStop + highlightStart + "Go" + highlightStart + " code",
+			want:     "<mark>Go code</mark>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightHTML(tt.headline, tt.unescape); got != tt.want {
+				t.Errorf("highlightHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to add one of the user's articles to the end of their series
+func (cfg *APIConfig) handlerSeriesArticlesAdd(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		ArticleID uuid.UUID `json:"article_id"`
+	}
+
+	seriesID, err := uuid.Parse(r.PathValue("seriesID")) // Extract the series ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid series ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	series, _, ok := cfg.getOwnedSeries(w, r, seriesID, userID)
+	if !ok {
+		return
+	}
+
+	// Only the author of an article can add it to a series
+	dbArticle, err := cfg.db.GetArticle(r.Context(), database.GetArticleParams{
+		ID:       params.ArticleID,
+		ViewerID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Article not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve article", err)
+		return
+	}
+	if dbArticle.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can only add your own articles to a series", nil)
+		return
+	}
+
+	err = cfg.db.AddSeriesArticle(r.Context(), database.AddSeriesArticleParams{
+		SeriesID:  seriesID,
+		ArticleID: params.ArticleID,
+	})
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			respondWithError(w, http.StatusConflict, "Article is already part of a series", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to add article to series", err)
+		return
+	}
+
+	cfg.respondWithSeries(w, r, series, userID)
+}
+
+// Handler function to change the order of the articles in a series, every article has to be listed exactly once
+func (cfg *APIConfig) handlerSeriesArticlesReorder(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		ArticleIDs []uuid.UUID `json:"article_ids"` // articles in their new order
+	}
+
+	seriesID, err := uuid.Parse(r.PathValue("seriesID")) // Extract the series ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid series ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err = decoder.Decode(&params)      // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	series, articles, ok := cfg.getOwnedSeries(w, r, seriesID, userID)
+	if !ok {
+		return
+	}
+
+	// A partial list would leave the other articles with clashing positions
+	remaining := make(map[uuid.UUID]bool, len(articles))
+	for _, article := range articles {
+		remaining[article.ID] = true
+	}
+	for _, id := range params.ArticleIDs {
+		if !remaining[id] {
+			respondWithError(w, http.StatusBadRequest, "article_ids contains an article that is not in the series or repeats one: "+id.String(), nil)
+			return
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		respondWithError(w, http.StatusBadRequest, "article_ids must list every article in the series", nil)
+		return
+	}
+
+	err = cfg.db.ReorderSeriesArticles(r.Context(), database.ReorderSeriesArticlesParams{
+		ArticleIds: params.ArticleIDs,
+		SeriesID:   seriesID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to reorder series articles", err)
+		return
+	}
+
+	cfg.respondWithSeries(w, r, series, userID)
+}
+
+// Handler function to remove an article from a series, the articles after it move up one position
+func (cfg *APIConfig) handlerSeriesArticlesRemove(w http.ResponseWriter, r *http.Request) {
+	seriesID, err := uuid.Parse(r.PathValue("seriesID")) // Extract the series ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid series ID", err)
+		return
+	}
+	articleID, err := uuid.Parse(r.PathValue("articleID")) // Extract the article ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid article ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	series, articles, ok := cfg.getOwnedSeries(w, r, seriesID, userID)
+	if !ok {
+		return
+	}
+
+	inSeries := false
+	for _, article := range articles {
+		if article.ID == articleID {
+			inSeries = true
+			break
+		}
+	}
+	if !inSeries {
+		respondWithError(w, http.StatusNotFound, "Article is not part of this series", nil)
+		return
+	}
+
+	err = cfg.db.RemoveSeriesArticle(r.Context(), database.RemoveSeriesArticleParams{
+		SeriesID:  seriesID,
+		ArticleID: articleID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to remove article from series", err)
+		return
+	}
+
+	cfg.respondWithSeries(w, r, series, userID)
+}
+
+// respondWithSeries responds with the series and its current list of articles as seen by the viewer
+func (cfg *APIConfig) respondWithSeries(w http.ResponseWriter, r *http.Request, series database.Series, viewer uuid.UUID) {
+	articles, err := cfg.db.GetSeriesArticles(r.Context(), database.GetSeriesArticlesParams{
+		SeriesID: series.ID,
+		ViewerID: viewer,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve series articles", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newSeries(series, articles))
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+const maxSeriesTitleLength = 200 // maximum number of characters in a series title
+
+type Series struct { // struct to hold series data
+	ID          uuid.UUID       `json:"id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UserID      uuid.UUID       `json:"user_id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Articles    []SeriesArticle `json:"articles"` // in reading order
+}
+
+type SeriesArticle struct { // struct to hold an article as listed in a series
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Position int32     `json:"position"`
+}
+
+type ArticleSeries struct { // struct to hold the series an article is part of
+	ID       uuid.UUID      `json:"id"`
+	Title    string         `json:"title"`
+	Position int32          `json:"position"`
+	Previous *SeriesArticle `json:"previous,omitempty"` // only included when retrieving a single article
+	Next     *SeriesArticle `json:"next,omitempty"`     // only included when retrieving a single article
+}
+
+// Handler function to create a new, empty series owned by the user
+func (cfg *APIConfig) handlerSeriesCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
+	params := parameters{}             // Create a new instance of the parameters struct
+	err := decoder.Decode(&params)     // Decode the request body into the parameters struct
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to decode request parameters", err)
+		return
+	}
+
+	title := strings.TrimSpace(params.Title)
+	if title == "" || utf8.RuneCountInString(title) > maxSeriesTitleLength {
+		respondWithError(w, http.StatusBadRequest, "Failed to validate request parameters", errors.New("title is required and can be at most 200 characters"))
+		return
+	}
+
+	series, err := cfg.db.CreateSeries(r.Context(), database.CreateSeriesParams{
+		UserID:      userID,
+		Title:       title,
+		Description: strings.TrimSpace(params.Description),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create series", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newSeries(series, nil))
+}
+
+// newSeries builds the Series response from a database series and its articles
+func newSeries(series database.Series, articles []database.GetSeriesArticlesRow) Series {
+	resp := Series{
+		ID:          series.ID,
+		CreatedAt:   series.CreatedAt,
+		UserID:      series.UserID,
+		Title:       series.Title,
+		Description: series.Description,
+		Articles:    []SeriesArticle{},
+	}
+	for _, article := range articles {
+		resp.Articles = append(resp.Articles, newSeriesArticle(article))
+	}
+	return resp
+}
+
+// newSeriesArticle builds the SeriesArticle response from a row of GetSeriesArticles
+func newSeriesArticle(row database.GetSeriesArticlesRow) SeriesArticle {
+	return SeriesArticle{
+		ID:       row.ID,
+		Title:    row.Title,
+		Status:   row.Status,
+		Position: row.Position,
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to retrieve a series with the articles the viewer can see
+func (cfg *APIConfig) handlerSeriesGet(w http.ResponseWriter, r *http.Request) {
+	seriesID, err := uuid.Parse(r.PathValue("seriesID")) // Extract the series ID from the URL
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid series ID", err)
+		return
+	}
+
+	series, err := cfg.db.GetSeries(r.Context(), seriesID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Series not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve series", err)
+		return
+	}
+
+	cfg.respondWithSeries(w, r, series, viewerID(r)) // drafts in the series are only listed for their author
+}
+
+// getOwnedSeries fetches a series with all of its articles and checks that it belongs to the user,
+// responding with an error and returning false otherwise
+func (cfg *APIConfig) getOwnedSeries(w http.ResponseWriter, r *http.Request, seriesID, userID uuid.UUID) (database.Series, []database.GetSeriesArticlesRow, bool) {
+	series, err := cfg.db.GetSeries(r.Context(), seriesID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Series not found", err)
+			return series, nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve series", err)
+		return series, nil, false
+	}
+	if series.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can not change this series", nil)
+		return series, nil, false
+	}
+
+	// Every article in a series belongs to its owner, so the owner sees all of them
+	articles, err := cfg.db.GetSeriesArticles(r.Context(), database.GetSeriesArticlesParams{
+		SeriesID: seriesID,
+		ViewerID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve series articles", err)
+		return series, nil, false
+	}
+	return series, articles, true
+}
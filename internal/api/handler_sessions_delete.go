@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// Handler function to log out a session of the user, its refresh token and access tokens stop working
+func (cfg *APIConfig) handlerSessionsDelete(w http.ResponseWriter, r *http.Request) {
+	// Extract the session ID from the URL
+	sessionID, err := uuid.Parse(r.PathValue("sessionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID", err)
+		return
+	}
+
+	// Retrieve the user ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+
+	// Revoke the refresh tokens of the session, only when it belongs to the user
+	revoked, err := cfg.db.RevokeSession(r.Context(), database.RevokeSessionParams{
+		SessionID: sessionID,
+		UserID:    userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke session", err)
+		return
+	}
+	if revoked == 0 {
+		respondWithError(w, http.StatusNotFound, "Session not found", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Respond with 204 No Content
+}
+
+// Handler function to log out every session of the user except the one the request was made with
+func (cfg *APIConfig) handlerSessionsDeleteOthers(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user and session ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+	sessionID, _ := r.Context().Value("sessionID").(uuid.UUID)
+	if sessionID == uuid.Nil {
+		respondWithError(w, http.StatusBadRequest, "Access token has no session, refresh it and try again", nil)
+		return
+	}
+
+	_, err := cfg.db.RevokeOtherSessions(r.Context(), database.RevokeOtherSessionsParams{
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Respond with 204 No Content
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/google/uuid"
+)
+
+func TestSessionsDeleteEndsAccessTokens(t *testing.T) {
+	cfg, _ := newCountingConfig(0)
+	userID, sessionID := uuid.New(), uuid.New()
+	token, _ := auth.MakeJWT(userID, sessionID, "secret", time.Hour)
+	other, _ := auth.MakeJWT(userID, uuid.New(), "secret", time.Hour)
+	sessionless, _ := auth.MakeJWT(userID, uuid.Nil, "secret", time.Hour)
+
+	protected := cfg.middlewareAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	request := func(handler http.Handler, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/sessions/"+sessionID.String(), nil)
+		req.SetPathValue("sessionID", sessionID.String())
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := request(cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSessionsDelete)), other); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	// The access token of the ended session is turned away before it expires, other sessions carry on
+	if rec := request(protected, token); rec.Code != http.StatusUnauthorized {
+		t.Errorf("token of the ended session: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := request(protected, other); rec.Code != http.StatusNoContent {
+		t.Errorf("token of another session: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := request(protected, sessionless); rec.Code != http.StatusUnauthorized {
+		t.Errorf("token without a session: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
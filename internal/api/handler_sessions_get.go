@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a login of the user on one device, it stays alive as long as its refresh token is refreshed
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"` // the session the request was made with
+}
+
+// Handler function to list the sessions of the user that can still be refreshed, most recently used first
+func (cfg *APIConfig) handlerSessionsRetrieve(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user and session ID from the context
+	userID, ok := r.Context().Value("userID").(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized: missing user ID", nil)
+		return
+	}
+	sessionID, _ := r.Context().Value("sessionID").(uuid.UUID)
+
+	dbSessions, err := cfg.db.GetActiveSessions(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve sessions", err)
+		return
+	}
+
+	sessions := make([]Session, 0, len(dbSessions))
+	for _, dbSession := range dbSessions {
+		sessions = append(sessions, Session{
+			ID:         dbSession.ID,
+			CreatedAt:  dbSession.CreatedAt,
+			LastUsedAt: dbSession.LastUsedAt,
+			UserAgent:  dbSession.UserAgent,
+			IP:         dbSession.Ip,
+			Current:    dbSession.ID == sessionID,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, sessions)
+}
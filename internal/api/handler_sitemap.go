@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/database"
+)
+
+const (
+	sitemapMaxURLs  = 50000     // most URLs a single sitemap may list
+	sitemapCacheTTL = time.Hour // how long a generated sitemap is served before it is built again
+	sitemapMaxAge   = 60 * 60   // seconds crawlers and proxies may cache sitemaps and robots.txt
+	sitemapNS       = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// errSitemapNotFound is returned when building a numbered sitemap past the last one
+var errSitemapNotFound = errors.New("sitemap not found")
+
+// sitemapCounter remembers how many URLs the sitemaps list, so requests for numbered sitemaps past the last one
+// are turned away without asking the database
+type sitemapCounter struct {
+	mu      sync.Mutex
+	count   int64
+	counted time.Time
+}
+
+// sitemapURLCount returns the number of URLs in the sitemaps, counting them again once the count is older than sitemapCacheTTL
+func (cfg *APIConfig) sitemapURLCount(ctx context.Context) (int64, error) {
+	counter := &cfg.sitemapCount
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	if !counter.counted.IsZero() && time.Since(counter.counted) < sitemapCacheTTL {
+		return counter.count, nil
+	}
+	count, err := cfg.db.CountSitemapURLs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	counter.count, counter.counted = count, time.Now()
+	return count, nil
+}
+
+// Handler function to serve the sitemap of every public category and article, sites with more than sitemapMaxURLs
+// of them get a sitemap index that points at the numbered sitemaps instead
+func (cfg *APIConfig) handlerSitemap(w http.ResponseWriter, r *http.Request) {
+	cfg.serveSitemap(w, r, func() ([]byte, error) {
+		count, err := cfg.sitemapURLCount(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		if count <= sitemapMaxURLs {
+			return cfg.buildSitemap(r, 1)
+		}
+
+		type sitemap struct {
+			Loc string `xml:"loc"`
+		}
+		type sitemapIndex struct {
+			XMLName  xml.Name  `xml:"sitemapindex"`
+			XMLNS    string    `xml:"xmlns,attr"`
+			Sitemaps []sitemap `xml:"sitemap"`
+		}
+		index := sitemapIndex{XMLNS: sitemapNS}
+		for page := int64(1); (page-1)*sitemapMaxURLs < count; page++ {
+			index.Sitemaps = append(index.Sitemaps, sitemap{Loc: cfg.siteURL() + "/sitemaps/" + strconv.FormatInt(page, 10) + ".xml"})
+		}
+		return marshalXML(index)
+	})
+}
+
+// Handler function to serve one of the numbered sitemaps listed in the sitemap index
+func (cfg *APIConfig) handlerSitemapPage(w http.ResponseWriter, r *http.Request) {
+	pageString, ok := strings.CutSuffix(r.PathValue("file"), ".xml") // Extract the page number from the URL
+	page, err := strconv.Atoi(pageString)
+	if !ok || err != nil || page < 1 {
+		respondWithError(w, http.StatusNotFound, "Sitemap not found", err)
+		return
+	}
+
+	// Only the pages the sitemap index lists are built and cached, any number past them is not found
+	count, err := cfg.sitemapURLCount(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to count sitemap URLs", err)
+		return
+	}
+	if page > 1 && int64(page-1)*sitemapMaxURLs >= count {
+		respondWithError(w, http.StatusNotFound, "Sitemap not found", nil)
+		return
+	}
+
+	cfg.serveSitemap(w, r, func() ([]byte, error) {
+		return cfg.buildSitemap(r, page)
+	})
+}
+
+// Handler function to serve robots.txt, which points crawlers at the public pages and the sitemap
+func (cfg *APIConfig) handlerRobots(w http.ResponseWriter, r *http.Request) {
+	robots := strings.Join([]string{
+		"User-agent: *",
+		"Allow: /api/articles/by-slug/",
+		"Allow: /api/categories/",
+		"Allow: /feeds/",
+		"Disallow: /api/",
+		"Disallow: /admin/",
+		"",
+		"Sitemap: " + cfg.siteURL() + "/sitemap.xml",
+		"",
+	}, "\n")
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(sitemapMaxAge))
+	respondWithText(w, http.StatusOK, "text/plain", robots)
+}
+
+// serveSitemap responds with the sitemap for the request path, which build only creates when it is not cached
+func (cfg *APIConfig) serveSitemap(w http.ResponseWriter, r *http.Request, build func() ([]byte, error)) {
+	sitemap, err := cfg.sitemaps.get(r.URL.Path, build)
+	if err != nil {
+		if errors.Is(err, errSitemapNotFound) {
+			respondWithError(w, http.StatusNotFound, "Sitemap not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to build sitemap", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(sitemapMaxAge))
+	w.Header().Set("ETag", sitemap.etag)
+	http.ServeContent(w, r, "", sitemap.created, bytes.NewReader(sitemap.data))
+}
+
+// buildSitemap lists the categories followed by the articles, sitemapMaxURLs per page
+func (cfg *APIConfig) buildSitemap(r *http.Request, page int) ([]byte, error) {
+	dbURLs, err := cfg.db.GetSitemapURLs(r.Context(), database.GetSitemapURLsParams{
+		Limit:  sitemapMaxURLs,
+		Offset: int32((page - 1) * sitemapMaxURLs),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbURLs) == 0 && page > 1 {
+		return nil, errSitemapNotFound
+	}
+
+	type sitemapURL struct {
+		Loc     string `xml:"loc"`
+		Lastmod string `xml:"lastmod,omitempty"`
+	}
+	type urlset struct {
+		XMLName xml.Name     `xml:"urlset"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		URLs    []sitemapURL `xml:"url"`
+	}
+	sitemap := urlset{XMLNS: sitemapNS, URLs: make([]sitemapURL, 0, len(dbURLs))}
+	for _, dbURL := range dbURLs {
+		entry := sitemapURL{Loc: cfg.articleURL(dbURL.Slug)}
+		if dbURL.Kind == "category" {
+			entry.Loc = cfg.categoryURL(dbURL.Slug)
+		}
+		if dbURL.Lastmod.Valid {
+			entry.Lastmod = dbURL.Lastmod.Time.UTC().Format(time.RFC3339)
+		}
+		sitemap.URLs = append(sitemap.URLs, entry)
+	}
+	return marshalXML(sitemap)
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSitemapPagePastLastPage(t *testing.T) {
+	cfg, connector := newCountingConfig(1)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/sitemaps/2.xml", nil)
+		req.SetPathValue("file", "2.xml")
+		rec := httptest.NewRecorder()
+		cfg.handlerSitemapPage(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	}
+
+	// The URLs are counted once, later requests past the last page do not reach the database
+	if got := connector.queries.Load(); got != 1 {
+		t.Errorf("queries = %d, want 1", got)
+	}
+}
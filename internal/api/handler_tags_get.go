@@ -0,0 +1,95 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxArticleTags = 10 // maximum number of tags on a single article
+	maxTagLength   = 50 // maximum number of characters in a tag name
+)
+
+type Tag struct { // struct to hold tag data
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	ArticleCount int64     `json:"article_count"` // number of published articles with this tag
+}
+
+// Handler function to retrieve every tag in use, most used first
+func (cfg *APIConfig) handlerTagsRetrieve(w http.ResponseWriter, r *http.Request) {
+	dbTags, err := cfg.db.GetTags(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve tags", err)
+		return
+	}
+
+	tags := []Tag{}
+	for _, dbTag := range dbTags {
+		tags = append(tags, Tag{
+			ID:           dbTag.ID,
+			Name:         dbTag.Name,
+			Slug:         dbTag.Slug,
+			ArticleCount: dbTag.ArticleCount,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, tags)
+}
+
+// Handler function to retrieve the articles with a tag, supports the same filters, sorts and pagination as the article listing
+func (cfg *APIConfig) handlerTagArticles(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug") // Extract the tag slug from the URL
+
+	// Make sure the tag exists, so an unknown tag is not mistaken for one without articles
+	_, err := cfg.db.GetTagBySlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Tag not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve tag", err)
+		return
+	}
+
+	filters, err := parseArticleFilters(r) // parse query parameters for filtering
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	filters.Tag = sql.NullString{String: slug, Valid: true} // the tag in the path takes precedence over a tag parameter
+
+	cfg.retrieveArticles(w, r, filters)
+}
+
+// normalizeTags trims the tag names of an article and derives their slugs, names that share a slug are only kept once
+func normalizeTags(tags []string) (names, slugs []string, err error) {
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		name := strings.TrimSpace(tag)
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, nil, fmt.Errorf("tag %q is too long", name)
+		}
+		slug := slugify(name)
+		if slug == "" {
+			return nil, nil, fmt.Errorf("tag %q must contain a letter or digit", name)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		names = append(names, name)
+		slugs = append(slugs, slug)
+	}
+	if len(slugs) > maxArticleTags {
+		return nil, nil, fmt.Errorf("an article can have at most %d tags", maxArticleTags)
+	}
+	return names, slugs, nil
+}
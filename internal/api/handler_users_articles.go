@@ -1,12 +1,14 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 
-	"github.com/GitIBB/pursuit/internal/database"
 	"github.com/google/uuid"
 )
 
+// Handler function to retrieve the articles of a single user, supports the same filters, sorts and pagination as the article listing
 func (cfg *APIConfig) handlerUserArticles(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.PathValue("userID")
 	userID, err := uuid.Parse(userIDStr)
@@ -15,19 +17,23 @@ func (cfg *APIConfig) handlerUserArticles(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Optional: support pagination
-	limit := 100 // or parse from query
-	offset := 0  // or parse from query
+	// Make sure the user exists, so an unknown user is not mistaken for one without articles
+	_, err = cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user", err)
+		return
+	}
 
-	articles, err := cfg.db.GetArticlesByUserId(r.Context(), database.GetArticlesByUserIdParams{
-		UserID: userID,
-		Limit:  int32(limit),
-		Offset: int32(offset),
-	})
+	filters, err := parseArticleFilters(r) // parse query parameters for filtering
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user's articles", err)
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
 		return
 	}
+	filters.UserID = uuid.NullUUID{UUID: userID, Valid: true} // the user in the path takes precedence over a user_id parameter
 
-	respondWithJSON(w, http.StatusOK, articles)
+	cfg.retrieveArticles(w, r, filters)
 }
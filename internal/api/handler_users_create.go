@@ -2,7 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/GitIBB/pursuit/internal/auth"
@@ -11,14 +16,45 @@ import (
 )
 
 type User struct { // struct to hold user data
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Email     string    `json:"email"`
-	Username  string    `json:"username"`
-	Password  string    `json:"password"`
+	ID              uuid.UUID  `json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"` // nil until the address is confirmed through the mailed link
+	Username        string     `json:"username"`
+	Password        string     `json:"password"`
 }
 
+// newUser converts a database user to the API shape, leaving out the password hash
+func newUser(user database.User) User {
+	u := User{
+		ID:        user.ID,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Email:     user.Email,
+		Username:  user.Username,
+	}
+	if user.EmailVerifiedAt.Valid {
+		u.EmailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+	return u
+}
+
+// validateEmail checks that an address is a plain address like name@example.com, without a display name
+func validateEmail(email string) error {
+	if len(email) > maxEmailLength {
+		return fmt.Errorf("email is longer than %d characters", maxEmailLength)
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || !strings.Contains(email[strings.LastIndex(email, "@")+1:], ".") {
+		return errors.New("email is not a valid address")
+	}
+	return nil
+}
+
+// maxEmailLength is the longest address that can be delivered to
+const maxEmailLength = 254
+
 func (cfg *APIConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request) {
 	type parameters struct { // struct to hold the request parameters
 		Password string `json:"password"`
@@ -28,7 +64,7 @@ func (cfg *APIConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 	type response struct { // struct to hold the response data
 		User
 		Token        string `json:"token"`
-		RefreshToken string `json:"refresh_token"`
+		RefreshToken string `json:"refresh_token,omitempty"` // only for clients that are not browsers, browsers get a cookie
 	}
 
 	decoder := json.NewDecoder(r.Body) // Create a new JSON decoder for the request body
@@ -39,6 +75,11 @@ func (cfg *APIConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := validateEmail(params.Email); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
 	hashedPassword, err := auth.HashPassword(params.Password) // Hash the password using the HashPassword function
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to hash password", err)
@@ -57,39 +98,32 @@ func (cfg *APIConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Generate JWT token
-	accessToken, err := auth.MakeJWT(user.ID, cfg.jwtSecret, time.Hour)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to create access JWT", err)
-		return
+	// Mail a link to confirm the address, the account works without it unless verified addresses are required
+	if err := cfg.sendEmailVerification(r, user, user.Email); err != nil {
+		log.Printf("Failed to send verification mail to new user %s: %v", user.ID, err)
 	}
 
-	// Generate refresh token
-	refreshToken, err := auth.MakeRefreshToken()
+	session, refreshToken, err := cfg.startSession(r, user.ID) // Start a new session with its first refresh token, only its hash is saved
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to create refresh token", err)
 		return
 	}
 
-	// Save refresh token in the database
-	_, err = cfg.db.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Hour * 24 * 60), // 60 days
-	})
+	// Generate JWT token
+	accessToken, err := auth.MakeJWT(user.ID, session.ID, cfg.jwtSecret, time.Hour)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to save refresh token in database", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create access JWT", err)
 		return
 	}
 
+	// Browser clients get cookies like they do at login, the refresh token is kept out of reach of scripts
+	if IsBrowser(r) {
+		setSessionCookies(w, accessToken, refreshToken)
+		refreshToken = ""
+	}
+
 	respondWithJSON(w, http.StatusCreated, response{ // Create a new response instance containing the user data
-		User: User{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Email:     user.Email,
-			Username:  user.Username,
-		},
+		User:         newUser(user),
 		Token:        accessToken,
 		RefreshToken: refreshToken,
 	})
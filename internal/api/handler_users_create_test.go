@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email   string
+		wantErr bool
+	}{
+		{email: "reader@example.com"},
+		{email: "first.last+news@mail.example.org"},
+		{email: "", wantErr: true},
+		{email: "not an address", wantErr: true},
+		{email: "reader@localhost", wantErr: true},
+		{email: "Reader <reader@example.com>", wantErr: true},
+		{email: " reader@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if err := validateEmail(tt.email); (err != nil) != tt.wantErr {
+				t.Errorf("validateEmail(%q) error = %v, wantErr %v", tt.email, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsersCreateKeepsRefreshTokenFromBrowsers(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		userAgent   string
+		wantCookies bool
+	}{
+		{name: "Browser", userAgent: "Mozilla/5.0 (X11; Linux x86_64)", wantCookies: true},
+		{name: "Other client", userAgent: "pursuit-cli/1.0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := newCountingConfig(0)
+			handler := cfg.middlewareBrowserAwareness(http.HandlerFunc(cfg.handlerUsersCreate))
+			body := `{"email": "reader@example.com", "username": "reader", "password": "correct horse battery staple"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(body))
+			req.Header.Set("User-Agent", tt.userAgent)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+			}
+
+			var response struct {
+				RefreshToken string `json:"refresh_token"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			cookies := map[string]bool{}
+			for _, cookie := range rec.Result().Cookies() {
+				cookies[cookie.Name] = cookie.HttpOnly && cookie.Value != ""
+			}
+			if tt.wantCookies && (response.RefreshToken != "" || !cookies[refreshTokenCookie] || !cookies["auth-token"]) {
+				t.Errorf("refresh_token = %q, cookies = %v, want only HttpOnly cookies", response.RefreshToken, rec.Result().Cookies())
+			}
+			if !tt.wantCookies && (response.RefreshToken == "" || len(cookies) != 0) {
+				t.Errorf("refresh_token = %q, cookies = %v, want the token in the body", response.RefreshToken, rec.Result().Cookies())
+			}
+		})
+	}
+}
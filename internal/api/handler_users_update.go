@@ -17,6 +17,7 @@ func (cfg *APIConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 	}
 	type response struct {
 		User
+		PendingEmail string `json:"pending_email,omitempty"` // address waiting to be confirmed through the mailed link
 	}
 
 	// Retrieve the user ID from the context
@@ -34,6 +35,22 @@ func (cfg *APIConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	current, err := cfg.db.GetUserByID(r.Context(), userID) // retrieve the current user data
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user", err)
+		return
+	}
+
+	// a new address only replaces the current one once the user confirms it through the link mailed to it
+	pendingEmail := ""
+	if params.Email != current.Email {
+		if err := validateEmail(params.Email); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		pendingEmail = params.Email
+	}
+
 	hashedPassword, err := auth.HashPassword(params.Password) // hash password
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to hash password", err)
@@ -42,7 +59,7 @@ func (cfg *APIConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 
 	user, err := cfg.db.UpdateUser(r.Context(), database.UpdateUserParams{ // update user data in db
 		ID:             userID,
-		Email:          params.Email,
+		Email:          current.Email,
 		Username:       params.Username,
 		HashedPassword: hashedPassword,
 	})
@@ -51,13 +68,16 @@ func (cfg *APIConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if pendingEmail != "" {
+		err = cfg.sendEmailVerification(r, user, pendingEmail)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to send verification mail", err)
+			return
+		}
+	}
+
 	respondWithJSON(w, http.StatusOK, response{ // respond with updated user data
-		User: User{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Email:     user.Email,
-			Username:  user.Username,
-		},
+		User:         newUser(user),
+		PendingEmail: pendingEmail,
 	})
 }
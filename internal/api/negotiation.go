@@ -0,0 +1,73 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// negotiateContentType picks the media type among the offers that the Accept header of the request prefers.
+// The earlier offer wins ties, and the first offer is used when the request has no Accept header or accepts none of them.
+func negotiateContentType(r *http.Request, offers ...string) string {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return offers[0]
+	}
+
+	best, bestQuality := offers[0], 0.0
+	for _, offer := range offers {
+		quality, specificity := 0.0, -1
+		for _, accepted := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err != nil {
+				continue
+			}
+			// The most specific media range that matches the offer decides its quality
+			rangeSpecificity := mediaRangeMatch(mediaType, offer)
+			if rangeSpecificity <= specificity {
+				continue
+			}
+			specificity, quality = rangeSpecificity, 1.0
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+				quality = q
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
+// mediaRangeMatch reports how specifically a media range from an Accept header matches a media type,
+// 2 for the type itself, 1 for type/*, 0 for */* and -1 when it does not match
+func mediaRangeMatch(mediaRange, mediaType string) int {
+	rangeType, rangeSubtype, _ := strings.Cut(mediaRange, "/")
+	typ, _, _ := strings.Cut(mediaType, "/")
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case rangeType == typ && rangeSubtype == "*":
+		return 1
+	case mediaRange == "*/*":
+		return 0
+	}
+	return -1
+}
+
+// requestMediaType returns the media type of the request body without its parameters
+func requestMediaType(r *http.Request) string {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// respondWithText responds with a text payload of the given media type, encoded as UTF-8
+func respondWithText(w http.ResponseWriter, code int, mediaType, payload string) {
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write([]byte(payload))
+}
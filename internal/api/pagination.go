@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultPageLimit = 10 // default number of records per page
+
+// parsePagination reads the page and limit query parameters, falling back to defaults for missing or invalid values
+func parsePagination(r *http.Request) (pageNum, limitNum int) {
+	page := r.URL.Query().Get("page")   // Get the page query parameter from the URL
+	limit := r.URL.Query().Get("limit") // Get the limit query parameter from the URL
+
+	// Default values for pagination
+	pageNum = 1
+	limitNum = defaultPageLimit
+
+	// Convert query parameters to integers
+	if page != "" {
+		if p, err := strconv.Atoi(page); err == nil && p > 0 {
+			pageNum = p // Set pageNum to the parsed value if it's a valid positive integer
+		}
+	}
+	if limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			limitNum = l
+		}
+	}
+	return pageNum, limitNum
+}
+
+// countPages calculates the number of pages needed to hold totalRecords, rounding up
+func countPages(totalRecords int64, limitNum int) int {
+	return (int(totalRecords) + limitNum - 1) / limitNum
+}
+
+// articleCursor marks a position in a listing ordered by creation time.
+// A cursor selects the records after that position, or the ones before it when Before is set.
+type articleCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+// encodeCursor turns a cursor into the opaque string handed out to clients
+func encodeCursor(cursor articleCursor) string {
+	data, _ := json.Marshal(cursor) // marshaling a time and a UUID can not fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor string created by encodeCursor
+func decodeCursor(value string) (articleCursor, error) {
+	cursor := articleCursor{}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return cursor, err
+	}
+	if cursor.CreatedAt.IsZero() || cursor.ID == uuid.Nil {
+		return cursor, errors.New("cursor is missing its position")
+	}
+	return cursor, nil
+}
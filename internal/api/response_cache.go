@@ -0,0 +1,84 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// responseCache keeps generated documents in memory for a while, so expensive responses are not rebuilt
+// from the database on every request
+type responseCache struct {
+	mu      sync.Mutex // guards entries only, documents are built under the lock of their own entry
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry holds the document cached under one key, its lock is held while the document is built so concurrent
+// requests for the same key wait for it instead of building it again, while other keys are served meanwhile
+type cacheEntry struct {
+	mu       sync.Mutex
+	response cachedResponse
+}
+
+type cachedResponse struct {
+	data    []byte
+	etag    string // quoted hash of data for conditional requests
+	created time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: map[string]*cacheEntry{},
+	}
+}
+
+// get returns the document cached under the key, building and caching it when it is missing or expired
+func (c *responseCache) get(key string, build func() ([]byte, error)) (cachedResponse, error) {
+	entry := c.entry(key)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.response.data != nil && now.Sub(entry.response.created) < c.ttl {
+		return entry.response, nil
+	}
+
+	data, err := build()
+	if err != nil {
+		return cachedResponse{}, err
+	}
+
+	hash := sha256.Sum256(data)
+	entry.response = cachedResponse{data: data, etag: `"` + hex.EncodeToString(hash[:16]) + `"`, created: now}
+	return entry.response, nil
+}
+
+// entry returns the entry for the key, adding an empty one when there is none
+func (c *responseCache) entry(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok {
+		return entry
+	}
+
+	// Drop the expired documents while we are at it, so the cache does not grow with keys nobody asks for anymore,
+	// entries that are being built are locked and left alone
+	now := time.Now()
+	for cachedKey, cached := range c.entries {
+		if !cached.mu.TryLock() {
+			continue
+		}
+		if now.Sub(cached.response.created) >= c.ttl {
+			delete(c.entries, cachedKey)
+		}
+		cached.mu.Unlock()
+	}
+	entry = &cacheEntry{}
+	c.entries[key] = entry
+	return entry
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	cache := newResponseCache(time.Hour)
+	builds := 0
+	build := func() ([]byte, error) {
+		builds++
+		return []byte("<urlset/>"), nil
+	}
+
+	first, err := cache.get("/sitemap.xml", build)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	second, err := cache.get("/sitemap.xml", build)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if builds != 1 {
+		t.Errorf("builds = %d, want 1", builds)
+	}
+	if first.etag == "" || first.etag != second.etag || !first.created.Equal(second.created) {
+		t.Errorf("second get() = %+v, want the cached %+v", second, first)
+	}
+
+	// Failed builds are not cached, the next request tries again
+	failing := errors.New("database is down")
+	if _, err := cache.get("/sitemaps/2.xml", func() ([]byte, error) { return nil, failing }); !errors.Is(err, failing) {
+		t.Errorf("get() error = %v, want %v", err, failing)
+	}
+	if _, err := cache.get("/sitemaps/2.xml", build); err != nil || builds != 2 {
+		t.Errorf("get() after a failed build: error = %v, builds = %d, want nil and 2", err, builds)
+	}
+
+	// Expired documents are built again
+	cache.ttl = 0
+	if _, err := cache.get("/sitemap.xml", build); err != nil || builds != 3 {
+		t.Errorf("get() after expiry: error = %v, builds = %d, want nil and 3", err, builds)
+	}
+}
+
+func TestResponseCacheBuildsKeysConcurrently(t *testing.T) {
+	cache := newResponseCache(time.Hour)
+	building := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.get("/sitemaps/1.xml", func() ([]byte, error) {
+			close(building)
+			<-release
+			return []byte("<urlset/>"), nil
+		})
+	}()
+	<-building
+
+	// Another document is served while the first one is still being built
+	if _, err := cache.get("/sitemap.xml", func() ([]byte, error) { return []byte("<sitemapindex/>"), nil }); err != nil {
+		t.Errorf("get() error = %v", err)
+	}
+	close(release)
+	<-done
+}
@@ -12,15 +12,28 @@ func (cfg *APIConfig) SetupRoutes(mux *http.ServeMux) {
 
 	// Auth endpoints
 	mux.Handle("POST /api/login", cfg.middlewareBrowserAwareness(http.HandlerFunc(cfg.handlerLogin))) // Register login endpoint at /login path, delegates handling to the handlerLogin function
-	mux.Handle("POST /api/logout", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerLogout)))           // Register logout endpoint at /logout path, delegates handling to the handlerLogout function
+	mux.HandleFunc("POST /api/logout", cfg.handlerLogout)                                             // Register logout endpoint at /logout path, delegates handling to the handlerLogout function
 	mux.HandleFunc("POST /api/refresh", cfg.handlerRefresh)                                           // Register refresh endpoint at /refresh path, delegates handling to the handlerRefresh function
 	mux.HandleFunc("POST /api/revoke", cfg.handlerRevoke)                                             // Register revoke endpoint at /revoke path, delegates handling to the handlerRevoke function
 
+	// Password endpoints
+	mux.HandleFunc("POST /api/password/forgot", cfg.handlerPasswordForgot) // Register password forgot endpoint at /password/forgot path, delegates handling to the handlerPasswordForgot function
+	mux.HandleFunc("POST /api/password/reset", cfg.handlerPasswordReset)   // Register password reset endpoint at /password/reset path, delegates handling to the handlerPasswordReset function
+
+	// Email verification endpoints
+	mux.Handle("POST /api/email/verification", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerEmailVerificationSend))) // Register verification mail endpoint at /email/verification path, delegates handling to the handlerEmailVerificationSend function
+	mux.HandleFunc("POST /api/email/confirm", cfg.handlerEmailConfirm)                                                 // Register email confirmation endpoint at /email/confirm path, delegates handling to the handlerEmailConfirm function
+
+	// Session endpoints
+	mux.Handle("GET /api/sessions", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSessionsRetrieve)))              // Register session retrieval endpoint at /sessions path, delegates handling to the handlerSessionsRetrieve function
+	mux.Handle("DELETE /api/sessions", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSessionsDeleteOthers)))       // Register log out everywhere else endpoint at /sessions path, delegates handling to the handlerSessionsDeleteOthers function
+	mux.Handle("DELETE /api/sessions/{sessionID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSessionsDelete))) // Register session deletion endpoint at /sessions/{sessionID} path, delegates handling to the handlerSessionsDelete function
+
 	// User endpoints
-	mux.HandleFunc("POST /api/users", cfg.handlerUsersCreate)                                  // Register user creation endpoint at /users path, delegates handling to the handlerUsersCreate function
-	mux.Handle("PUT /api/users", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerUsersUpdate))) // Register user update endpoint at /users path, delegates handling to the handlerUsersUpdate function
-	mux.Handle("GET /api/users", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerUsersGet)))    // Register user retrieval endpoint at /users path, delegates handling to the handlerUsersGet function
-	mux.Handle("GET /api/me", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerMe)))             // Register user (me) retrieval endpoint at /me path, delegates handling to the handlerMe function
+	mux.Handle("POST /api/users", cfg.middlewareBrowserAwareness(http.HandlerFunc(cfg.handlerUsersCreate))) // Register user creation endpoint at /users path, delegates handling to the handlerUsersCreate function
+	mux.Handle("PUT /api/users", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerUsersUpdate)))              // Register user update endpoint at /users path, delegates handling to the handlerUsersUpdate function
+	mux.Handle("GET /api/users", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerUsersGet)))                 // Register user retrieval endpoint at /users path, delegates handling to the handlerUsersGet function
+	mux.Handle("GET /api/me", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerMe)))                          // Register user (me) retrieval endpoint at /me path, delegates handling to the handlerMe function
 
 	// Uploads endpoints
 	// TEMPORARY USAGE: REPLACE STORAGE IN UPLOADS FOLDER WITH CDN OR BUCKET STORAGE IN PRODUCTION
@@ -32,14 +45,65 @@ func (cfg *APIConfig) SetupRoutes(mux *http.ServeMux) {
 	mux.Handle("POST /api/uploads", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerUploads)))
 
 	// Article endpoints
-	mux.Handle("POST /api/articles", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerArticlesCreate)))               // Register article creation endpoint at /articles path, delegates handling to the handlerArticlesCreate function
-	mux.HandleFunc("GET /api/articles", cfg.handlerArticlesRetrieve)                                                // Register article (all) retrieval endpoint at /articles path, delegates handling to the handlerArticlesRetrieve function
-	mux.HandleFunc("GET /api/articles/{articleID}", cfg.handlerArticlesGet)                                         // Register article retrieval endpoint at /articles/{articleID} path, delegates handling to the handlerArticlesGet function
-	mux.Handle("DELETE /api/articles/{articleID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerArticlesDelete))) // Register article deletion endpoint at /articles/{articleID} path, delegates handling to the handlerArticlesDelete function
-	mux.HandleFunc("GET /api/users/{userID}/articles", cfg.handlerUserArticles)                                     // Register user articles retrieval endpoint at /users/{userID}/articles path, delegates handling to the handlerUserArticles function
-
-	// Category endpoint
-	mux.HandleFunc("GET /api/categories", cfg.handlerCategoriesGet) // Register categories retrieval endpoint at /categories path, delegates handling to the handlerCategoriesGet function
+	mux.Handle("POST /api/articles", cfg.middlewareVerifiedEmail(http.HandlerFunc(cfg.handlerArticlesCreate)))                     // Register article creation endpoint at /articles path, delegates handling to the handlerArticlesCreate function
+	mux.Handle("GET /api/articles", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerArticlesRetrieve)))                     // Register article (all) retrieval endpoint at /articles path, delegates handling to the handlerArticlesRetrieve function
+	mux.Handle("GET /api/articles/{articleID}", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerArticlesGet)))              // Register article retrieval endpoint at /articles/{articleID} path, delegates handling to the handlerArticlesGet function
+	mux.Handle("PUT /api/articles/{articleID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerArticlesUpdate)))                   // Register article update endpoint at /articles/{articleID} path, delegates handling to the handlerArticlesUpdate function
+	mux.Handle("PATCH /api/articles/{articleID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerArticlesUpdate)))                 // Register article (partial) update endpoint at /articles/{articleID} path, delegates handling to the handlerArticlesUpdate function
+	mux.Handle("PUT /api/articles/{articleID}/status", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerArticlesStatus)))            // Register article status endpoint at /articles/{articleID}/status path, delegates handling to the handlerArticlesStatus function
+	mux.Handle("DELETE /api/articles/{articleID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerArticlesDelete)))                // Register article deletion endpoint at /articles/{articleID} path, delegates handling to the handlerArticlesDelete function
+	mux.Handle("GET /api/users/{userID}/articles", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerUserArticles)))          // Register user articles retrieval endpoint at /users/{userID}/articles path, delegates handling to the handlerUserArticles function
+	mux.Handle("GET /api/articles/{articleID}/{slug}", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerArticlesGetBySlug))) // Register article retrieval by slug endpoint at /articles/by-slug/{slug} path, delegates handling to the handlerArticlesGetBySlug function
+
+	// Search endpoint
+	mux.Handle("GET /api/search", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerSearch))) // Register search endpoint at /search path, delegates handling to the handlerSearch function
+
+	// Revision endpoints
+	mux.Handle("GET /api/articles/{articleID}/revisions", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerRevisionsRetrieve)))                    // Register revision (all) retrieval endpoint at /articles/{articleID}/revisions path, delegates handling to the handlerRevisionsRetrieve function
+	mux.Handle("GET /api/articles/{articleID}/revisions/diff", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerRevisionsDiff)))                   // Register revision diff endpoint at /articles/{articleID}/revisions/diff path, delegates handling to the handlerRevisionsDiff function
+	mux.Handle("GET /api/articles/{articleID}/revisions/{revision}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerRevisionsGet)))              // Register revision retrieval endpoint at /articles/{articleID}/revisions/{revision} path, delegates handling to the handlerRevisionsGet function
+	mux.Handle("POST /api/articles/{articleID}/revisions/{revision}/restore", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerRevisionsRestore))) // Register revision restore endpoint at /articles/{articleID}/revisions/{revision}/restore path, delegates handling to the handlerRevisionsRestore function
+
+	// Comment endpoints
+	mux.Handle("POST /api/articles/{articleID}/comments", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerCommentsCreate)))               // Register comment creation endpoint at /articles/{articleID}/comments path, delegates handling to the handlerCommentsCreate function
+	mux.Handle("GET /api/articles/{articleID}/comments", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerCommentsRetrieve)))      // Register comment retrieval endpoint at /articles/{articleID}/comments path, delegates handling to the handlerCommentsRetrieve function
+	mux.Handle("PUT /api/articles/{articleID}/comments/{commentID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerCommentsUpdate)))    // Register comment update endpoint at /articles/{articleID}/comments/{commentID} path, delegates handling to the handlerCommentsUpdate function
+	mux.Handle("DELETE /api/articles/{articleID}/comments/{commentID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerCommentsDelete))) // Register comment deletion endpoint at /articles/{articleID}/comments/{commentID} path, delegates handling to the handlerCommentsDelete function
+
+	// Series endpoints
+	mux.Handle("POST /api/series", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSeriesCreate)))                                           // Register series creation endpoint at /series path, delegates handling to the handlerSeriesCreate function
+	mux.Handle("GET /api/series/{seriesID}", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerSeriesGet)))                            // Register series retrieval endpoint at /series/{seriesID} path, delegates handling to the handlerSeriesGet function
+	mux.Handle("POST /api/series/{seriesID}/articles", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSeriesArticlesAdd)))                  // Register series article addition endpoint at /series/{seriesID}/articles path, delegates handling to the handlerSeriesArticlesAdd function
+	mux.Handle("PUT /api/series/{seriesID}/articles", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSeriesArticlesReorder)))               // Register series article reorder endpoint at /series/{seriesID}/articles path, delegates handling to the handlerSeriesArticlesReorder function
+	mux.Handle("DELETE /api/series/{seriesID}/articles/{articleID}", cfg.middlewareAuth(http.HandlerFunc(cfg.handlerSeriesArticlesRemove))) // Register series article removal endpoint at /series/{seriesID}/articles/{articleID} path, delegates handling to the handlerSeriesArticlesRemove function
+
+	// Tag endpoints
+	mux.HandleFunc("GET /api/tags", cfg.handlerTagsRetrieve)                                                          // Register tags retrieval endpoint at /tags path, delegates handling to the handlerTagsRetrieve function
+	mux.Handle("GET /api/tags/{slug}/articles", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerTagArticles))) // Register tag articles retrieval endpoint at /tags/{slug}/articles path, delegates handling to the handlerTagArticles function
+
+	// Category endpoints, changes are limited to admins
+	mux.HandleFunc("GET /api/categories", cfg.handlerCategoriesGet)                                                              // Register categories retrieval endpoint at /categories path, delegates handling to the handlerCategoriesGet function
+	mux.Handle("GET /api/categories/{slug}/articles", cfg.middlewareOptionalAuth(http.HandlerFunc(cfg.handlerCategoryArticles))) // Register category articles retrieval endpoint at /categories/{slug}/articles path, delegates handling to the handlerCategoryArticles function
+	mux.Handle("POST /api/categories", cfg.middlewareAdmin(http.HandlerFunc(cfg.handlerCategoriesCreate)))                       // Register category creation endpoint at /categories path, delegates handling to the handlerCategoriesCreate function
+	mux.Handle("PUT /api/categories/order", cfg.middlewareAdmin(http.HandlerFunc(cfg.handlerCategoriesReorder)))                 // Register category reorder endpoint at /categories/order path, delegates handling to the handlerCategoriesReorder function
+	mux.Handle("PUT /api/categories/{categoryID}", cfg.middlewareAdmin(http.HandlerFunc(cfg.handlerCategoriesUpdate)))           // Register category update endpoint at /categories/{categoryID} path, delegates handling to the handlerCategoriesUpdate function
+	mux.Handle("DELETE /api/categories/{categoryID}", cfg.middlewareAdmin(http.HandlerFunc(cfg.handlerCategoriesDelete)))        // Register category deletion endpoint at /categories/{categoryID} path, delegates handling to the handlerCategoriesDelete function
+
+	// Feed endpoints
+	mux.HandleFunc("GET /feeds/articles.rss", cfg.handlerFeeds(FeedFormatRSS))                     // Register site RSS feed endpoint at /feeds/articles.rss path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/articles.atom", cfg.handlerFeeds(FeedFormatAtom))                   // Register site Atom feed endpoint at /feeds/articles.atom path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/articles.json", cfg.handlerFeeds(FeedFormatJSON))                   // Register site JSON feed endpoint at /feeds/articles.json path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/categories/{slug}/articles.rss", cfg.handlerFeeds(FeedFormatRSS))   // Register category RSS feed endpoint at /feeds/categories/{slug}/articles.rss path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/categories/{slug}/articles.atom", cfg.handlerFeeds(FeedFormatAtom)) // Register category Atom feed endpoint at /feeds/categories/{slug}/articles.atom path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/categories/{slug}/articles.json", cfg.handlerFeeds(FeedFormatJSON)) // Register category JSON feed endpoint at /feeds/categories/{slug}/articles.json path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/users/{userID}/articles.rss", cfg.handlerFeeds(FeedFormatRSS))      // Register author RSS feed endpoint at /feeds/users/{userID}/articles.rss path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/users/{userID}/articles.atom", cfg.handlerFeeds(FeedFormatAtom))    // Register author Atom feed endpoint at /feeds/users/{userID}/articles.atom path, delegates handling to the handlerFeeds function
+	mux.HandleFunc("GET /feeds/users/{userID}/articles.json", cfg.handlerFeeds(FeedFormatJSON))    // Register author JSON feed endpoint at /feeds/users/{userID}/articles.json path, delegates handling to the handlerFeeds function
+
+	// Sitemap endpoints
+	mux.HandleFunc("GET /sitemap.xml", cfg.handlerSitemap)         // Register sitemap endpoint at /sitemap.xml path, delegates handling to the handlerSitemap function
+	mux.HandleFunc("GET /sitemaps/{file}", cfg.handlerSitemapPage) // Register numbered sitemap endpoint at /sitemaps/{page}.xml path, delegates handling to the handlerSitemapPage function
+	mux.HandleFunc("GET /robots.txt", cfg.handlerRobots)           // Register robots endpoint at /robots.txt path, delegates handling to the handlerRobots function
 
 	// Admin endpoints
 	mux.HandleFunc("GET /admin/metrics", cfg.handlerMetrics) // Register metrics endpoint at /metrics path, delegates handling to the handlerMetrics function
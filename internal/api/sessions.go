@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/auth"
+	"github.com/GitIBB/pursuit/internal/database"
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLength is the number of bytes of the User-Agent header kept with a session
+const maxUserAgentLength = 512
+
+// startSession records a new session for the client of the request and creates its first refresh token, it returns
+// the token for the client while the database only gets its hash
+func (cfg *APIConfig) startSession(r *http.Request, userID uuid.UUID) (database.Session, string, error) {
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return database.Session{}, "", err
+	}
+
+	// Both rows are written together, a session without a refresh token would be listed but could never be used
+	var session database.Session
+	err = cfg.inTx(r.Context(), func(q *database.Queries) error {
+		session, err = q.CreateSession(r.Context(), database.CreateSessionParams{
+			UserID:    userID,
+			UserAgent: clientUserAgent(r),
+			Ip:        clientIP(r),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+			TokenHash: auth.HashToken(refreshToken),
+			UserID:    userID,
+			ExpiresAt: time.Now().Add(refreshTokenDuration),
+			SessionID: session.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return database.Session{}, "", err
+	}
+	return session, refreshToken, nil
+}
+
+// clientUserAgent returns the User-Agent header, cut off so a client cannot fill the sessions table with it
+func clientUserAgent(r *http.Request) string {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+	return userAgent
+}
+
+// clientIP returns the address of the client, the first X-Forwarded-For entry when the API runs behind a proxy.
+// It is only shown to the user to recognize their sessions, so a client setting the header itself does no harm.
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		if parsed := net.ParseIP(strings.TrimSpace(ip)); parsed != nil {
+			return parsed.String()
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{name: "Remote address", remoteAddr: "192.0.2.1:54321", want: "192.0.2.1"},
+		{name: "IPv6 remote address", remoteAddr: "[2001:db8::1]:443", want: "2001:db8::1"},
+		{name: "First forwarded address", remoteAddr: "10.0.0.2:80", forwarded: "198.51.100.7, 10.0.0.1", want: "198.51.100.7"},
+		{name: "Invalid forwarded address", remoteAddr: "10.0.0.2:80", forwarded: "<script>", want: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/sessions", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"strings"
+	"unicode"
+)
+
+// slugify lowercases a name and joins its letters and digits with hyphens, "Go & Web Dev" becomes "go-web-dev"
+func slugify(name string) string {
+	var b strings.Builder
+	pendingHyphen := false // set when a separator was skipped since the last letter or digit
+	for _, r := range strings.ToLower(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -38,9 +39,24 @@ func CheckPasswordHash(password, hash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
+// AccessClaims are the claims of an access token, the session ID names the session it was issued for
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
+// AccessToken is what a valid access token identifies
+type AccessToken struct {
+	ID        string // the jti claim, empty for tokens issued before access tokens had one
+	UserID    uuid.UUID
+	SessionID uuid.UUID // uuid.Nil for tokens issued before sessions were recorded
+	ExpiresAt time.Time
+}
+
 // MakeJWT token
 func MakeJWT(
 	userID uuid.UUID,
+	sessionID uuid.UUID,
 	tokenSecret string,
 	expiresIn time.Duration,
 ) (string, error) {
@@ -56,46 +72,75 @@ func MakeJWT(
 	if expiresIn == 0 { // Check if the expiration duration is zero
 		return "", errors.New("empty expiration duration")
 	}
+	claims := AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    string(TokenTypeAccess),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+			Subject:   userID.String(),
+			ID:        uuid.NewString(), // Lets a single token be denied after logout
+		},
+	}
+	if sessionID != uuid.Nil {
+		claims.SessionID = sessionID.String()
+	}
 	// Create a new JWT Token with specified signing method and claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    string(TokenTypeAccess),
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
-		Subject:   userID.String(),
-	})
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(signingKey) // Sign the token with the signing key and return it
 }
 
 // Validate JWT -
 func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-	claimsStruct := jwt.RegisteredClaims{} // Create a new instance of the RegisteredClaims struct
-	token, err := jwt.ParseWithClaims(     // Parse the token string and validate it using the provided secret
+	accessToken, err := ParseJWT(tokenString, tokenSecret)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return accessToken.UserID, nil
+}
+
+// ParseJWT validates an access token and returns the user and session it was issued for
+func ParseJWT(tokenString, tokenSecret string) (AccessToken, error) {
+	claimsStruct := AccessClaims{}     // Create a new instance of the AccessClaims struct
+	token, err := jwt.ParseWithClaims( // Parse the token string and validate it using the provided secret
 		tokenString,
 		&claimsStruct,
 		func(token *jwt.Token) (interface{}, error) { return []byte(tokenSecret), nil },
 	)
 	if err != nil {
-		return uuid.Nil, err
+		return AccessToken{}, err
 	}
 
 	userIDString, err := token.Claims.GetSubject() // Get the subject (user ID) from the token claims
 	if err != nil {
-		return uuid.Nil, err
+		return AccessToken{}, err
 	}
 
 	issuer, err := token.Claims.GetIssuer() // Get the issuer from the token claims
 	if err != nil {
-		return uuid.Nil, err
+		return AccessToken{}, err
 	}
 	if issuer != string(TokenTypeAccess) { // Check if the issuer matches the expected token type
-		return uuid.Nil, errors.New("invalid token issuer")
+		return AccessToken{}, errors.New("invalid token issuer")
 	}
 
 	id, err := uuid.Parse(userIDString) // Parse the user ID string into a UUID
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user ID: %w", err) // Return an error if the user ID is invalid
+		return AccessToken{}, fmt.Errorf("invalid user ID: %w", err) // Return an error if the user ID is invalid
 	}
-	return id, nil // Return the parsed user ID
+
+	expiresAt, err := token.Claims.GetExpirationTime() // Get the expiration time, tokens are only denied until then
+	if err != nil || expiresAt == nil {
+		return AccessToken{}, errors.New("missing token expiration")
+	}
+
+	accessToken := AccessToken{ID: claimsStruct.ID, UserID: id, ExpiresAt: expiresAt.Time}
+	if claimsStruct.SessionID != "" { // Tokens issued before sessions were recorded have no session ID
+		accessToken.SessionID, err = uuid.Parse(claimsStruct.SessionID)
+		if err != nil {
+			return AccessToken{}, fmt.Errorf("invalid session ID: %w", err)
+		}
+	}
+	return accessToken, nil // Return the parsed user and session ID
 }
 
 // GetBearerToken
@@ -123,3 +168,15 @@ func MakeRefreshToken() (string, error) {
 	}
 	return hex.EncodeToString(token), nil // Encode the byte slice to a hex string and return it
 }
+
+// MakeToken makes a random 256 bit token encoded in hex, for the single use links sent by mail
+func MakeToken() (string, error) {
+	return MakeRefreshToken()
+}
+
+// HashToken returns the hex encoded SHA-256 hash of a refresh token or a token made by MakeToken, only the hash
+// is stored in the database
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token)) // The tokens are random, a fast unsalted hash is enough to look them up
+	return hex.EncodeToString(hash[:])
+}
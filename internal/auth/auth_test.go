@@ -65,7 +65,7 @@ func TestCheckPasswordHash(t *testing.T) {
 
 func TestValidateJWT(t *testing.T) {
 	userID := uuid.New()
-	validToken, _ := MakeJWT(userID, "secret", time.Hour)
+	validToken, _ := MakeJWT(userID, uuid.New(), "secret", time.Hour)
 
 	tests := []struct {
 		name        string
@@ -111,6 +111,37 @@ func TestValidateJWT(t *testing.T) {
 	}
 }
 
+func TestParseJWT(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	token, _ := MakeJWT(userID, sessionID, "secret", time.Hour)
+	got, err := ParseJWT(token, "secret")
+	if err != nil {
+		t.Fatalf("ParseJWT() error = %v", err)
+	}
+	if got.UserID != userID || got.SessionID != sessionID {
+		t.Errorf("ParseJWT() = %+v, want user %v and session %v", got, userID, sessionID)
+	}
+	if got.ID == "" || got.ExpiresAt.Before(time.Now()) {
+		t.Errorf("ParseJWT() = %+v, want a token ID and a future expiration", got)
+	}
+	other, _ := MakeJWT(userID, sessionID, "secret", time.Hour)
+	if otherToken, _ := ParseJWT(other, "secret"); otherToken.ID == got.ID {
+		t.Errorf("MakeJWT() gave two tokens the same ID %q", got.ID)
+	}
+
+	// Tokens without a session are still valid, they just do not name one
+	token, _ = MakeJWT(userID, uuid.Nil, "secret", time.Hour)
+	got, err = ParseJWT(token, "secret")
+	if err != nil {
+		t.Fatalf("ParseJWT() error = %v", err)
+	}
+	if got.UserID != userID || got.SessionID != uuid.Nil {
+		t.Errorf("ParseJWT() = %+v, want user %v without a session", got, userID)
+	}
+}
+
 func TestGetBearerToken(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -155,3 +186,19 @@ func TestGetBearerToken(t *testing.T) {
 		})
 	}
 }
+
+func TestHashToken(t *testing.T) {
+	// Must match encode(sha256(...), 'hex'), which hashed the tokens stored before hashing was introduced
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := HashToken("abc"); got != want {
+		t.Errorf("HashToken() = %v, want %v", got, want)
+	}
+
+	token, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("MakeRefreshToken() error = %v", err)
+	}
+	if HashToken(token) == token {
+		t.Errorf("HashToken() returned the token itself")
+	}
+}
@@ -12,10 +12,70 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countArticles = `-- name: CountArticles :one
+SELECT COUNT(*)
+FROM articles a
+JOIN categories ON a.category_id = categories.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = $1
+)
+AND ($2::uuid IS NULL OR a.user_id = $2)
+AND ($3::uuid IS NULL OR a.category_id = $3)
+AND ($4::text IS NULL OR categories.name = $4)
+AND ($5::timestamp IS NULL OR a.created_at >= $5)
+AND ($6::timestamp IS NULL OR a.created_at < $6)
+AND ($7::text IS NULL OR a.status = $7)
+AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = $8
+))
+AND ($9::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = $9
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+`
+
+type CountArticlesParams struct {
+	ViewerID     uuid.UUID
+	UserID       uuid.NullUUID
+	CategoryID   uuid.NullUUID
+	CategoryName sql.NullString
+	CreatedFrom  sql.NullTime
+	CreatedTo    sql.NullTime
+	Status       sql.NullString
+	Tag          sql.NullString
+	CategorySlug sql.NullString
+}
+
+func (q *Queries) CountArticles(ctx context.Context, arg CountArticlesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countArticles,
+		arg.ViewerID,
+		arg.UserID,
+		arg.CategoryID,
+		arg.CategoryName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Status,
+		arg.Tag,
+		arg.CategorySlug,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createArticle = `-- name: CreateArticle :one
-INSERT INTO articles (id, created_at, updated_at, user_id, category_id, title, body, image_url)
+INSERT INTO articles (id, created_at, updated_at, user_id, category_id, title, body, image_url, status, published_at, publish_at, slug)
 VALUES (
     gen_random_uuid(),
     NOW(),
@@ -24,9 +84,13 @@ VALUES (
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6,
+    CASE WHEN $6 = 'published' THEN NOW() END,
+    $7,
+    $8
 )
-RETURNING id, created_at, updated_at, user_id, category_id, title, body, image_url
+RETURNING id, created_at, updated_at, user_id, category_id, title, body, image_url, status, published_at, publish_at, view_count, slug, search_vector
 `
 
 type CreateArticleParams struct {
@@ -35,6 +99,9 @@ type CreateArticleParams struct {
 	Title      string
 	Body       json.RawMessage
 	ImageUrl   sql.NullString
+	Status     string
+	PublishAt  sql.NullTime
+	Slug       string
 }
 
 func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (Article, error) {
@@ -44,6 +111,9 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (A
 		arg.Title,
 		arg.Body,
 		arg.ImageUrl,
+		arg.Status,
+		arg.PublishAt,
+		arg.Slug,
 	)
 	var i Article
 	err := row.Scan(
@@ -55,6 +125,12 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (A
 		&i.Title,
 		&i.Body,
 		&i.ImageUrl,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.ViewCount,
+		&i.Slug,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -70,26 +146,52 @@ func (q *Queries) DeleteArticle(ctx context.Context, id uuid.UUID) error {
 }
 
 const getArticle = `-- name: GetArticle :one
-Select a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, users.username
+Select a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, a.status, a.published_at, a.publish_at, a.view_count, a.slug, a.search_vector, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
 FROM articles a
 JOIN users on a.user_id = users.id
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
 WHERE a.id = $1
+AND (
+    a.status IN ('published', 'archived')
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = $2
+)
 `
 
+type GetArticleParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.UUID
+}
+
 type GetArticleRow struct {
-	ID         uuid.UUID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	UserID     uuid.UUID
-	CategoryID uuid.UUID
-	Title      string
-	Body       json.RawMessage
-	ImageUrl   sql.NullString
-	Username   string
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uuid.UUID
+	CategoryID     uuid.UUID
+	Title          string
+	Body           json.RawMessage
+	ImageUrl       sql.NullString
+	Status         string
+	PublishedAt    sql.NullTime
+	PublishAt      sql.NullTime
+	ViewCount      int64
+	Slug           string
+	SearchVector   interface{}
+	Username       string
+	CategoryName   string
+	Tags           []string
+	SeriesID       uuid.NullUUID
+	SeriesTitle    sql.NullString
+	SeriesPosition sql.NullInt32
 }
 
-func (q *Queries) GetArticle(ctx context.Context, id uuid.UUID) (GetArticleRow, error) {
-	row := q.db.QueryRowContext(ctx, getArticle, id)
+func (q *Queries) GetArticle(ctx context.Context, arg GetArticleParams) (GetArticleRow, error) {
+	row := q.db.QueryRowContext(ctx, getArticle, arg.ID, arg.ViewerID)
 	var i GetArticleRow
 	err := row.Scan(
 		&i.ID,
@@ -100,38 +202,117 @@ func (q *Queries) GetArticle(ctx context.Context, id uuid.UUID) (GetArticleRow,
 		&i.Title,
 		&i.Body,
 		&i.ImageUrl,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.ViewCount,
+		&i.Slug,
+		&i.SearchVector,
 		&i.Username,
+		&i.CategoryName,
+		pq.Array(&i.Tags),
+		&i.SeriesID,
+		&i.SeriesTitle,
+		&i.SeriesPosition,
 	)
 	return i, err
 }
 
 const getArticles = `-- name: GetArticles :many
-SELECT a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, users.username
+SELECT a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, a.status, a.published_at, a.publish_at, a.view_count, a.slug, a.search_vector, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
 FROM articles a
 JOIN users ON a.user_id = users.id
-ORDER BY a.created_at ASC
-LIMIT $1 OFFSET $2
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = $1
+)
+AND ($2::uuid IS NULL OR a.user_id = $2)
+AND ($3::uuid IS NULL OR a.category_id = $3)
+AND ($4::text IS NULL OR categories.name = $4)
+AND ($5::timestamp IS NULL OR a.created_at >= $5)
+AND ($6::timestamp IS NULL OR a.created_at < $6)
+AND ($7::text IS NULL OR a.status = $7)
+AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = $8
+))
+AND ($9::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = $9
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+ORDER BY
+    CASE WHEN $10::text = 'newest' THEN a.created_at END DESC,
+    CASE WHEN $10::text = 'title' THEN a.title END ASC,
+    CASE WHEN $10::text = 'most_viewed' THEN a.view_count END DESC,
+    a.created_at ASC,
+    a.id ASC
+LIMIT $11 OFFSET $12
 `
 
 type GetArticlesParams struct {
-	Limit  int32
-	Offset int32
+	ViewerID     uuid.UUID
+	UserID       uuid.NullUUID
+	CategoryID   uuid.NullUUID
+	CategoryName sql.NullString
+	CreatedFrom  sql.NullTime
+	CreatedTo    sql.NullTime
+	Status       sql.NullString
+	Tag          sql.NullString
+	CategorySlug sql.NullString
+	Sort         string
+	Limit        int32
+	Offset       int32
 }
 
 type GetArticlesRow struct {
-	ID         uuid.UUID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	UserID     uuid.UUID
-	CategoryID uuid.UUID
-	Title      string
-	Body       json.RawMessage
-	ImageUrl   sql.NullString
-	Username   string
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uuid.UUID
+	CategoryID     uuid.UUID
+	Title          string
+	Body           json.RawMessage
+	ImageUrl       sql.NullString
+	Status         string
+	PublishedAt    sql.NullTime
+	PublishAt      sql.NullTime
+	ViewCount      int64
+	Slug           string
+	SearchVector   interface{}
+	Username       string
+	CategoryName   string
+	Tags           []string
+	SeriesID       uuid.NullUUID
+	SeriesTitle    sql.NullString
+	SeriesPosition sql.NullInt32
 }
 
 func (q *Queries) GetArticles(ctx context.Context, arg GetArticlesParams) ([]GetArticlesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getArticles, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, getArticles,
+		arg.ViewerID,
+		arg.UserID,
+		arg.CategoryID,
+		arg.CategoryName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Status,
+		arg.Tag,
+		arg.CategorySlug,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +329,18 @@ func (q *Queries) GetArticles(ctx context.Context, arg GetArticlesParams) ([]Get
 			&i.Title,
 			&i.Body,
 			&i.ImageUrl,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.ViewCount,
+			&i.Slug,
+			&i.SearchVector,
 			&i.Username,
+			&i.CategoryName,
+			pq.Array(&i.Tags),
+			&i.SeriesID,
+			&i.SeriesTitle,
+			&i.SeriesPosition,
 		); err != nil {
 			return nil, err
 		}
@@ -163,42 +355,107 @@ func (q *Queries) GetArticles(ctx context.Context, arg GetArticlesParams) ([]Get
 	return items, nil
 }
 
-const getArticlesByUserId = `-- name: GetArticlesByUserId :many
-SELECT a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, users.username
+const getArticlesAfter = `-- name: GetArticlesAfter :many
+SELECT a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, a.status, a.published_at, a.publish_at, a.view_count, a.slug, a.search_vector, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
 FROM articles a
 JOIN users ON a.user_id = users.id
-WHERE a.user_id = $1
-ORDER BY a.created_at ASC
-LIMIT $2 OFFSET $3
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = $1
+)
+AND ($2::uuid IS NULL OR a.user_id = $2)
+AND ($3::uuid IS NULL OR a.category_id = $3)
+AND ($4::text IS NULL OR categories.name = $4)
+AND ($5::timestamp IS NULL OR a.created_at >= $5)
+AND ($6::timestamp IS NULL OR a.created_at < $6)
+AND ($7::text IS NULL OR a.status = $7)
+AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = $8
+))
+AND ($9::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = $9
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+AND (
+    $10::timestamp IS NULL
+    OR (a.created_at, a.id) > ($10, $11::uuid)
+)
+ORDER BY a.created_at ASC, a.id ASC
+LIMIT $12
 `
 
-type GetArticlesByUserIdParams struct {
-	UserID uuid.UUID
-	Limit  int32
-	Offset int32
+type GetArticlesAfterParams struct {
+	ViewerID        uuid.UUID
+	UserID          uuid.NullUUID
+	CategoryID      uuid.NullUUID
+	CategoryName    sql.NullString
+	CreatedFrom     sql.NullTime
+	CreatedTo       sql.NullTime
+	Status          sql.NullString
+	Tag             sql.NullString
+	CategorySlug    sql.NullString
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	Limit           int32
 }
 
-type GetArticlesByUserIdRow struct {
-	ID         uuid.UUID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	UserID     uuid.UUID
-	CategoryID uuid.UUID
-	Title      string
-	Body       json.RawMessage
-	ImageUrl   sql.NullString
-	Username   string
+type GetArticlesAfterRow struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uuid.UUID
+	CategoryID     uuid.UUID
+	Title          string
+	Body           json.RawMessage
+	ImageUrl       sql.NullString
+	Status         string
+	PublishedAt    sql.NullTime
+	PublishAt      sql.NullTime
+	ViewCount      int64
+	Slug           string
+	SearchVector   interface{}
+	Username       string
+	CategoryName   string
+	Tags           []string
+	SeriesID       uuid.NullUUID
+	SeriesTitle    sql.NullString
+	SeriesPosition sql.NullInt32
 }
 
-func (q *Queries) GetArticlesByUserId(ctx context.Context, arg GetArticlesByUserIdParams) ([]GetArticlesByUserIdRow, error) {
-	rows, err := q.db.QueryContext(ctx, getArticlesByUserId, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) GetArticlesAfter(ctx context.Context, arg GetArticlesAfterParams) ([]GetArticlesAfterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArticlesAfter,
+		arg.ViewerID,
+		arg.UserID,
+		arg.CategoryID,
+		arg.CategoryName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Status,
+		arg.Tag,
+		arg.CategorySlug,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetArticlesByUserIdRow
+	var items []GetArticlesAfterRow
 	for rows.Next() {
-		var i GetArticlesByUserIdRow
+		var i GetArticlesAfterRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -208,7 +465,18 @@ func (q *Queries) GetArticlesByUserId(ctx context.Context, arg GetArticlesByUser
 			&i.Title,
 			&i.Body,
 			&i.ImageUrl,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.ViewCount,
+			&i.Slug,
+			&i.SearchVector,
 			&i.Username,
+			&i.CategoryName,
+			pq.Array(&i.Tags),
+			&i.SeriesID,
+			&i.SeriesTitle,
+			&i.SeriesPosition,
 		); err != nil {
 			return nil, err
 		}
@@ -223,13 +491,387 @@ func (q *Queries) GetArticlesByUserId(ctx context.Context, arg GetArticlesByUser
 	return items, nil
 }
 
-const getTotalArticlesCount = `-- name: GetTotalArticlesCount :one
-SELECT COUNT(*) FROM articles
+const getArticlesBefore = `-- name: GetArticlesBefore :many
+SELECT a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, a.status, a.published_at, a.publish_at, a.view_count, a.slug, a.search_vector, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
+FROM articles a
+JOIN users ON a.user_id = users.id
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = $1
+)
+AND ($2::uuid IS NULL OR a.user_id = $2)
+AND ($3::uuid IS NULL OR a.category_id = $3)
+AND ($4::text IS NULL OR categories.name = $4)
+AND ($5::timestamp IS NULL OR a.created_at >= $5)
+AND ($6::timestamp IS NULL OR a.created_at < $6)
+AND ($7::text IS NULL OR a.status = $7)
+AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = $8
+))
+AND ($9::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = $9
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+AND (
+    $10::timestamp IS NULL
+    OR (a.created_at, a.id) < ($10, $11::uuid)
+)
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT $12
 `
 
-func (q *Queries) GetTotalArticlesCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getTotalArticlesCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type GetArticlesBeforeParams struct {
+	ViewerID        uuid.UUID
+	UserID          uuid.NullUUID
+	CategoryID      uuid.NullUUID
+	CategoryName    sql.NullString
+	CreatedFrom     sql.NullTime
+	CreatedTo       sql.NullTime
+	Status          sql.NullString
+	Tag             sql.NullString
+	CategorySlug    sql.NullString
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	Limit           int32
+}
+
+type GetArticlesBeforeRow struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uuid.UUID
+	CategoryID     uuid.UUID
+	Title          string
+	Body           json.RawMessage
+	ImageUrl       sql.NullString
+	Status         string
+	PublishedAt    sql.NullTime
+	PublishAt      sql.NullTime
+	ViewCount      int64
+	Slug           string
+	SearchVector   interface{}
+	Username       string
+	CategoryName   string
+	Tags           []string
+	SeriesID       uuid.NullUUID
+	SeriesTitle    sql.NullString
+	SeriesPosition sql.NullInt32
+}
+
+func (q *Queries) GetArticlesBefore(ctx context.Context, arg GetArticlesBeforeParams) ([]GetArticlesBeforeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArticlesBefore,
+		arg.ViewerID,
+		arg.UserID,
+		arg.CategoryID,
+		arg.CategoryName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Status,
+		arg.Tag,
+		arg.CategorySlug,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArticlesBeforeRow
+	for rows.Next() {
+		var i GetArticlesBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.CategoryID,
+			&i.Title,
+			&i.Body,
+			&i.ImageUrl,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.ViewCount,
+			&i.Slug,
+			&i.SearchVector,
+			&i.Username,
+			&i.CategoryName,
+			pq.Array(&i.Tags),
+			&i.SeriesID,
+			&i.SeriesTitle,
+			&i.SeriesPosition,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDueArticles = `-- name: PublishDueArticles :many
+UPDATE articles
+SET status = 'published', published_at = publish_at, updated_at = NOW()
+WHERE status = 'scheduled'
+AND id IN (
+    SELECT id FROM articles
+    WHERE status = 'scheduled' AND publish_at <= NOW()
+    ORDER BY publish_at
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id
+`
+
+func (q *Queries) PublishDueArticles(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, publishDueArticles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateArticle = `-- name: UpdateArticle :one
+WITH old_slug AS (
+    INSERT INTO article_slug_history (slug, article_id, created_at)
+    SELECT slug, id, NOW() FROM articles
+    WHERE id = $1 AND slug <> $6
+    ON CONFLICT (slug) DO NOTHING
+), reclaimed_slug AS (
+    DELETE FROM article_slug_history
+    WHERE slug = $6 AND article_id = $1
+)
+UPDATE articles
+SET category_id = $2, title = $3, body = $4, image_url = $5, slug = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, user_id, category_id, title, body, image_url, status, published_at, publish_at, view_count, slug, search_vector
+`
+
+type UpdateArticleParams struct {
+	ID         uuid.UUID
+	CategoryID uuid.UUID
+	Title      string
+	Body       json.RawMessage
+	ImageUrl   sql.NullString
+	Slug       string
+}
+
+func (q *Queries) UpdateArticle(ctx context.Context, arg UpdateArticleParams) (Article, error) {
+	row := q.db.QueryRowContext(ctx, updateArticle,
+		arg.ID,
+		arg.CategoryID,
+		arg.Title,
+		arg.Body,
+		arg.ImageUrl,
+		arg.Slug,
+	)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.CategoryID,
+		&i.Title,
+		&i.Body,
+		&i.ImageUrl,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.ViewCount,
+		&i.Slug,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const updateArticleStatus = `-- name: UpdateArticleStatus :one
+UPDATE articles
+SET status = $1,
+    published_at = CASE WHEN $1 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+    publish_at = CASE WHEN $1 = 'scheduled' THEN $2::timestamptz ELSE publish_at END,
+    updated_at = NOW()
+WHERE id = $3
+RETURNING id, created_at, updated_at, user_id, category_id, title, body, image_url, status, published_at, publish_at, view_count, slug, search_vector
+`
+
+type UpdateArticleStatusParams struct {
+	Status    string
+	PublishAt sql.NullTime
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateArticleStatus(ctx context.Context, arg UpdateArticleStatusParams) (Article, error) {
+	row := q.db.QueryRowContext(ctx, updateArticleStatus, arg.Status, arg.PublishAt, arg.ID)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.CategoryID,
+		&i.Title,
+		&i.Body,
+		&i.ImageUrl,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.ViewCount,
+		&i.Slug,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const viewArticle = `-- name: ViewArticle :one
+WITH viewed AS (
+    UPDATE articles SET view_count = view_count + 1
+    WHERE (id = $1 OR slug = $2)
+    AND user_id <> $3
+    AND (
+        status IN ('published', 'archived')
+        OR (status = 'scheduled' AND publish_at <= NOW())
+    )
+    RETURNING id
+)
+SELECT a.id, a.created_at, a.updated_at, a.user_id, a.category_id, a.title, a.body, a.image_url, a.status, a.published_at, a.publish_at, a.view_count, a.slug, a.search_vector, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position,
+    neighbours.previous_id, neighbours.previous_title, neighbours.previous_status, neighbours.previous_position,
+    neighbours.next_id, neighbours.next_title, neighbours.next_status, neighbours.next_position
+FROM articles a
+JOIN users ON a.user_id = users.id
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+LEFT JOIN LATERAL (
+    SELECT * FROM (
+        SELECT sa.article_id,
+            lag(s.id) OVER w AS previous_id, lag(s.title) OVER w AS previous_title,
+            lag(s.status) OVER w AS previous_status, lag(sa.position) OVER w AS previous_position,
+            lead(s.id) OVER w AS next_id, lead(s.title) OVER w AS next_title,
+            lead(s.status) OVER w AS next_status, lead(sa.position) OVER w AS next_position
+        FROM series_articles sa
+        JOIN articles s ON sa.article_id = s.id
+        WHERE sa.series_id = series_articles.series_id
+        AND (
+            s.status IN ('published', 'archived')
+            OR (s.status = 'scheduled' AND s.publish_at <= NOW())
+            OR s.user_id = $3
+        )
+        WINDOW w AS (ORDER BY sa.position)
+    ) series_neighbours
+    WHERE series_neighbours.article_id = a.id
+) neighbours ON true
+WHERE (a.id = $1 OR a.slug = $2)
+AND (
+    a.status IN ('published', 'archived')
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = $3
+)
+`
+
+type ViewArticleParams struct {
+	ID       uuid.NullUUID
+	Slug     sql.NullString
+	ViewerID uuid.UUID
+}
+
+type ViewArticleRow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	CategoryID       uuid.UUID
+	Title            string
+	Body             json.RawMessage
+	ImageUrl         sql.NullString
+	Status           string
+	PublishedAt      sql.NullTime
+	PublishAt        sql.NullTime
+	ViewCount        int64
+	Slug             string
+	SearchVector     interface{}
+	Username         string
+	CategoryName     string
+	Tags             []string
+	SeriesID         uuid.NullUUID
+	SeriesTitle      sql.NullString
+	SeriesPosition   sql.NullInt32
+	PreviousID       uuid.NullUUID
+	PreviousTitle    sql.NullString
+	PreviousStatus   sql.NullString
+	PreviousPosition sql.NullInt32
+	NextID           uuid.NullUUID
+	NextTitle        sql.NullString
+	NextStatus       sql.NullString
+	NextPosition     sql.NullInt32
+}
+
+func (q *Queries) ViewArticle(ctx context.Context, arg ViewArticleParams) (ViewArticleRow, error) {
+	row := q.db.QueryRowContext(ctx, viewArticle, arg.ID, arg.Slug, arg.ViewerID)
+	var i ViewArticleRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.CategoryID,
+		&i.Title,
+		&i.Body,
+		&i.ImageUrl,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.ViewCount,
+		&i.Slug,
+		&i.SearchVector,
+		&i.Username,
+		&i.CategoryName,
+		pq.Array(&i.Tags),
+		&i.SeriesID,
+		&i.SeriesTitle,
+		&i.SeriesPosition,
+		&i.PreviousID,
+		&i.PreviousTitle,
+		&i.PreviousStatus,
+		&i.PreviousPosition,
+		&i.NextID,
+		&i.NextTitle,
+		&i.NextStatus,
+		&i.NextPosition,
+	)
+	return i, err
 }
@@ -9,10 +9,92 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const createCategory = `-- name: CreateCategory :one
+INSERT INTO categories (id, name, position, parent_id, slug, description)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM categories),
+    $2,
+    $3,
+    $4
+)
+RETURNING id, name, position, parent_id, slug, description
+`
+
+type CreateCategoryParams struct {
+	Name        string
+	ParentID    uuid.NullUUID
+	Slug        string
+	Description string
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRowContext(ctx, createCategory,
+		arg.Name,
+		arg.ParentID,
+		arg.Slug,
+		arg.Description,
+	)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Position,
+		&i.ParentID,
+		&i.Slug,
+		&i.Description,
+	)
+	return i, err
+}
+
+const deleteCategory = `-- name: DeleteCategory :execrows
+WITH reparented AS (
+    UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = $1)
+    WHERE parent_id = $1
+)
+DELETE FROM categories
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCategory, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCategoryReassigningArticles = `-- name: DeleteCategoryReassigningArticles :execrows
+WITH reassigned AS (
+    UPDATE articles SET category_id = $1, updated_at = NOW()
+    WHERE category_id = $2
+), reparented AS (
+    UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = $2)
+    WHERE parent_id = $2
+)
+DELETE FROM categories
+WHERE id = $2
+`
+
+type DeleteCategoryReassigningArticlesParams struct {
+	ReassignTo uuid.UUID
+	ID         uuid.UUID
+}
+
+func (q *Queries) DeleteCategoryReassigningArticles(ctx context.Context, arg DeleteCategoryReassigningArticlesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCategoryReassigningArticles, arg.ReassignTo, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getCategories = `-- name: GetCategories :many
-SELECT id, name FROM categories ORDER BY name ASC
+SELECT id, name, position, parent_id, slug, description FROM categories ORDER BY position ASC, name ASC
 `
 
 func (q *Queries) GetCategories(ctx context.Context) ([]Category, error) {
@@ -24,7 +106,67 @@ func (q *Queries) GetCategories(ctx context.Context) ([]Category, error) {
 	var items []Category
 	for rows.Next() {
 		var i Category
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.ParentID,
+			&i.Slug,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategoriesWithArticleCounts = `-- name: GetCategoriesWithArticleCounts :many
+SELECT c.id, c.name, c.position, c.parent_id, c.slug, c.description, COUNT(a.id) AS article_count
+FROM categories c
+LEFT JOIN articles a ON a.category_id = c.id
+AND (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+)
+GROUP BY c.id
+ORDER BY c.position ASC, c.name ASC
+`
+
+type GetCategoriesWithArticleCountsRow struct {
+	ID           uuid.UUID
+	Name         string
+	Position     int32
+	ParentID     uuid.NullUUID
+	Slug         string
+	Description  string
+	ArticleCount int64
+}
+
+func (q *Queries) GetCategoriesWithArticleCounts(ctx context.Context) ([]GetCategoriesWithArticleCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCategoriesWithArticleCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCategoriesWithArticleCountsRow
+	for rows.Next() {
+		var i GetCategoriesWithArticleCountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.ParentID,
+			&i.Slug,
+			&i.Description,
+			&i.ArticleCount,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -39,12 +181,83 @@ func (q *Queries) GetCategories(ctx context.Context) ([]Category, error) {
 }
 
 const getCategoryByID = `-- name: GetCategoryByID :one
-SELECT id, name FROM categories WHERE id = $1
+SELECT id, name, position, parent_id, slug, description FROM categories WHERE id = $1
 `
 
 func (q *Queries) GetCategoryByID(ctx context.Context, id uuid.UUID) (Category, error) {
 	row := q.db.QueryRowContext(ctx, getCategoryByID, id)
 	var i Category
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Position,
+		&i.ParentID,
+		&i.Slug,
+		&i.Description,
+	)
+	return i, err
+}
+
+const getCategoryBySlug = `-- name: GetCategoryBySlug :one
+SELECT id, name, position, parent_id, slug, description FROM categories WHERE slug = $1
+`
+
+func (q *Queries) GetCategoryBySlug(ctx context.Context, slug string) (Category, error) {
+	row := q.db.QueryRowContext(ctx, getCategoryBySlug, slug)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Position,
+		&i.ParentID,
+		&i.Slug,
+		&i.Description,
+	)
+	return i, err
+}
+
+const reorderCategories = `-- name: ReorderCategories :exec
+UPDATE categories
+SET position = new_order.position
+FROM unnest($1::uuid[]) WITH ORDINALITY AS new_order(id, position)
+WHERE categories.id = new_order.id
+`
+
+func (q *Queries) ReorderCategories(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, reorderCategories, pq.Array(ids))
+	return err
+}
+
+const updateCategory = `-- name: UpdateCategory :one
+UPDATE categories SET name = $2, parent_id = $3, slug = $4, description = $5
+WHERE id = $1
+RETURNING id, name, position, parent_id, slug, description
+`
+
+type UpdateCategoryParams struct {
+	ID          uuid.UUID
+	Name        string
+	ParentID    uuid.NullUUID
+	Slug        string
+	Description string
+}
+
+func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error) {
+	row := q.db.QueryRowContext(ctx, updateCategory,
+		arg.ID,
+		arg.Name,
+		arg.ParentID,
+		arg.Slug,
+		arg.Description,
+	)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Position,
+		&i.ParentID,
+		&i.Slug,
+		&i.Description,
+	)
 	return i, err
 }
@@ -13,35 +13,136 @@ import (
 )
 
 type Article struct {
-	ID         uuid.UUID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	UserID     uuid.UUID
-	CategoryID uuid.UUID
-	Title      string
-	Body       json.RawMessage
-	ImageUrl   sql.NullString
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uuid.UUID
+	CategoryID   uuid.UUID
+	Title        string
+	Body         json.RawMessage
+	ImageUrl     sql.NullString
+	Status       string
+	PublishedAt  sql.NullTime
+	PublishAt    sql.NullTime
+	ViewCount    int64
+	Slug         string
+	SearchVector interface{}
+}
+
+type ArticleRevision struct {
+	ID             uuid.UUID
+	ArticleID      uuid.UUID
+	RevisionNumber int32
+	CreatedAt      time.Time
+	Event          string
+	UserID         uuid.UUID
+	CategoryID     uuid.UUID
+	Title          string
+	Body           json.RawMessage
+	ImageUrl       sql.NullString
+	Status         string
+}
+
+type ArticleSlugHistory struct {
+	Slug      string
+	ArticleID uuid.UUID
+	CreatedAt time.Time
+}
+
+type ArticleTag struct {
+	ArticleID uuid.UUID
+	TagID     uuid.UUID
 }
 
 type Category struct {
-	ID   uuid.UUID
-	Name string
+	ID          uuid.UUID
+	Name        string
+	Position    int32
+	ParentID    uuid.NullUUID
+	Slug        string
+	Description string
 }
 
-type RefreshToken struct {
-	Token     string
+type Comment struct {
+	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	ArticleID uuid.UUID
+	UserID    uuid.UUID
+	ParentID  uuid.NullUUID
+	Body      string
+}
+
+type DeniedAccessToken struct {
+	Jti       string
+	ExpiresAt time.Time
+}
+
+type EmailVerificationToken struct {
+	TokenHash string
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+}
+
+type PasswordResetToken struct {
+	TokenHash string
+	CreatedAt time.Time
 	UserID    uuid.UUID
 	ExpiresAt time.Time
-	RevokedAt sql.NullTime
+	UsedAt    sql.NullTime
+}
+
+type RefreshToken struct {
+	TokenHash  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+	SessionID  uuid.UUID
+	ReplacedBy sql.NullString
+}
+
+type Series struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UserID      uuid.UUID
+	Title       string
+	Description string
+}
+
+type SeriesArticle struct {
+	SeriesID  uuid.UUID
+	ArticleID uuid.UUID
+	Position  int32
+}
+
+type Session struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UserID     uuid.UUID
+	UserAgent  string
+	Ip         string
+	LastUsedAt time.Time
+}
+
+type Tag struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Name      string
+	Slug      string
 }
 
 type User struct {
-	ID             uuid.UUID
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Email          string
-	Username       string
-	HashedPassword string
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Email           string
+	Username        string
+	HashedPassword  string
+	IsAdmin         bool
+	EmailVerifiedAt sql.NullTime
 }
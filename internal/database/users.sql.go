@@ -21,7 +21,7 @@ VALUES (
     $2,
     $3
 )
-RETURNING id, created_at, updated_at, email, username, hashed_password
+RETURNING id, created_at, updated_at, email, username, hashed_password, is_admin, email_verified_at
 `
 
 type CreateUserParams struct {
@@ -40,12 +40,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.Username,
 		&i.HashedPassword,
+		&i.IsAdmin,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, email, username, hashed_password FROM users
+SELECT id, created_at, updated_at, email, username, hashed_password, is_admin, email_verified_at FROM users
 WHERE email = $1
 `
 
@@ -59,12 +61,14 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.Username,
 		&i.HashedPassword,
+		&i.IsAdmin,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, email, username, hashed_password FROM users
+SELECT id, created_at, updated_at, email, username, hashed_password, is_admin, email_verified_at FROM users
 WHERE id = $1
 `
 
@@ -78,6 +82,8 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Email,
 		&i.Username,
 		&i.HashedPassword,
+		&i.IsAdmin,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -85,7 +91,7 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 const updateUser = `-- name: UpdateUser :one
 UPDATE users SET email = $2, username = $3, hashed_password = $4, updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, username, hashed_password
+RETURNING id, created_at, updated_at, email, username, hashed_password, is_admin, email_verified_at
 `
 
 type UpdateUserParams struct {
@@ -110,6 +116,23 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.Username,
 		&i.HashedPassword,
+		&i.IsAdmin,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET hashed_password = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID             uuid.UUID
+	HashedPassword string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.ID, arg.HashedPassword)
+	return err
+}
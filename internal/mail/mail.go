@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a plain text mail to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers mail, the API only talks to this interface so the delivery can be swapped per environment
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogSender writes every message to a log instead of delivering it, for development only, the log gets the
+// working links from the mails
+type LogSender struct {
+	logger *log.Logger
+}
+
+// NewLogSender returns a LogSender that writes to w
+func NewLogSender(w io.Writer) *LogSender {
+	return &LogSender{logger: log.New(w, "[MAIL] ", log.LstdFlags)}
+}
+
+func (s *LogSender) Send(_ context.Context, msg Message) error {
+	s.logger.Printf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileSender writes every message to its own file in a directory instead of delivering it, so development setups
+// and tests can pick up the links that were sent
+type FileSender struct {
+	dir string
+}
+
+// NewFileSender returns a FileSender that writes to dir, creating it when it does not exist
+func NewFileSender(dir string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSender{dir: dir}, nil
+}
+
+func (s *FileSender) Send(_ context.Context, msg Message) error {
+	// Name the files by time so they list in the order they were sent
+	name := time.Now().UTC().Format("20060102T150405.000000000Z") + "-" + uuid.NewString()[:8] + ".eml"
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		headerValue(msg.To), headerValue(msg.Subject), strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(content), 0o644)
+}
+
+// headerValue keeps a value on its header line, so it cannot add headers of its own
+func headerValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
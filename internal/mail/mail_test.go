@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender, err := NewFileSender(dir)
+	if err != nil {
+		t.Fatalf("NewFileSender() error = %v", err)
+	}
+
+	err = sender.Send(context.Background(), Message{
+		To:      "reader@example.com\r\nBcc: everyone@example.com",
+		Subject: "Reset your password",
+		Body:    "Line one\nLine two",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("ReadDir() = %v, %v, want one file", files, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "To: reader@example.com Bcc: everyone@example.com\r\nSubject: Reset your password\r\n") {
+		t.Errorf("headers are not one per line:\n%s", content)
+	}
+	if !strings.HasSuffix(content, "\r\n\r\nLine one\r\nLine two\r\n") {
+		t.Errorf("body is missing:\n%s", content)
+	}
+}
@@ -0,0 +1,29 @@
+// Package mailtest provides a mail.Sender for tests
+package mailtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/GitIBB/pursuit/internal/mail"
+)
+
+// MemorySender keeps the messages it is given instead of delivering them
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []mail.Message
+}
+
+func (s *MemorySender) Send(_ context.Context, msg mail.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (s *MemorySender) Messages() []mail.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]mail.Message(nil), s.messages...)
+}
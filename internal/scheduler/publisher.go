@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/GitIBB/pursuit/internal/database"
+)
+
+// StartPublisher publishes scheduled articles once their publish_at has passed, checking every interval
+// until ctx is cancelled. PublishDueArticles locks the rows it publishes (SKIP LOCKED), so every server
+// replica can run its own publisher without articles being published twice.
+func StartPublisher(ctx context.Context, db *database.Queries, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			publishDueArticles(ctx, db)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func publishDueArticles(ctx context.Context, db *database.Queries) {
+	published, err := db.PublishDueArticles(ctx)
+	if err != nil {
+		log.Printf("Failed to publish scheduled articles: %v", err)
+		return
+	}
+	for _, articleID := range published {
+		log.Printf("Published scheduled article %s", articleID)
+	}
+}
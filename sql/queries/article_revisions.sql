@@ -0,0 +1,41 @@
+-- name: GetArticleRevisions :many
+SELECT * FROM article_revisions
+WHERE article_id = $1
+ORDER BY revision_number DESC;
+
+-- name: GetArticleRevision :one
+SELECT * FROM article_revisions
+WHERE article_id = $1 AND revision_number = $2;
+
+-- name: RestoreArticleRevision :one
+WITH old_slug AS (
+    INSERT INTO article_slug_history (slug, article_id, created_at)
+    SELECT slug, id, NOW() FROM articles
+    WHERE id = $1 AND slug <> $3
+    ON CONFLICT (slug) DO NOTHING
+), reclaimed_slug AS (
+    DELETE FROM article_slug_history
+    WHERE slug = $3 AND article_id = $1
+)
+INSERT INTO articles (id, created_at, updated_at, user_id, category_id, title, body, image_url, status, slug)
+SELECT
+    r.article_id,
+    (SELECT MIN(first.created_at) FROM article_revisions first WHERE first.article_id = r.article_id),
+    NOW(),
+    r.user_id,
+    r.category_id,
+    r.title,
+    CASE WHEN r.body ? 'version' THEN r.body ELSE article_blocks_from_legacy_body(r.body) END,
+    r.image_url,
+    'draft',
+    $3
+FROM article_revisions r
+WHERE r.article_id = $1 AND r.revision_number = $2
+ON CONFLICT (id) DO UPDATE
+SET category_id = EXCLUDED.category_id,
+    title = EXCLUDED.title,
+    body = EXCLUDED.body,
+    image_url = EXCLUDED.image_url,
+    slug = EXCLUDED.slug,
+    updated_at = NOW()
+RETURNING *;
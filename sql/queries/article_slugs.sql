@@ -0,0 +1,19 @@
+-- name: GetTakenArticleSlugs :many
+SELECT slug FROM articles
+WHERE (slug = sqlc.arg(slug) OR slug LIKE sqlc.arg(slug) || '-%')
+AND id <> sqlc.arg(article_id)
+UNION
+SELECT slug FROM article_slug_history
+WHERE (slug = sqlc.arg(slug) OR slug LIKE sqlc.arg(slug) || '-%')
+AND article_id <> sqlc.arg(article_id);
+
+-- name: GetArticleSlugRedirect :one
+SELECT a.slug
+FROM article_slug_history h
+JOIN articles a ON h.article_id = a.id
+WHERE h.slug = sqlc.arg(slug)
+AND (
+    a.status IN ('published', 'archived')
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+);
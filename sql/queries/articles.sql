@@ -1,5 +1,5 @@
 -- name: CreateArticle :one
-INSERT INTO articles (id, created_at, updated_at, user_id, category_id, title, body, image_url)
+INSERT INTO articles (id, created_at, updated_at, user_id, category_id, title, body, image_url, status, published_at, publish_at, slug)
 VALUES (
     gen_random_uuid(),
     NOW(),
@@ -8,34 +8,263 @@ VALUES (
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6,
+    CASE WHEN $6 = 'published' THEN NOW() END,
+    $7,
+    $8
 )
 RETURNING *;
 
 -- name: GetArticles :many
-SELECT a.*, users.username
+SELECT a.*, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
 FROM articles a
 JOIN users ON a.user_id = users.id
-ORDER BY a.created_at ASC
-LIMIT $1 OFFSET $2;
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+AND (sqlc.narg(user_id)::uuid IS NULL OR a.user_id = sqlc.narg(user_id))
+AND (sqlc.narg(category_id)::uuid IS NULL OR a.category_id = sqlc.narg(category_id))
+AND (sqlc.narg(category_name)::text IS NULL OR categories.name = sqlc.narg(category_name))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR a.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR a.created_at < sqlc.narg(created_to))
+AND (sqlc.narg(status)::text IS NULL OR a.status = sqlc.narg(status))
+AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = sqlc.narg(tag)
+))
+AND (sqlc.narg(category_slug)::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = sqlc.narg(category_slug)
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'newest' THEN a.created_at END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'title' THEN a.title END ASC,
+    CASE WHEN sqlc.arg(sort)::text = 'most_viewed' THEN a.view_count END DESC,
+    a.created_at ASC,
+    a.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GetArticle :one
-Select a.*, users.username
+Select a.*, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
 FROM articles a
 JOIN users on a.user_id = users.id
-WHERE a.id = $1;
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE a.id = sqlc.arg(id)
+AND (
+    a.status IN ('published', 'archived')
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+);
 
 -- name: DeleteArticle :exec
 DELETE FROM articles
 where id = $1;
 
--- name: GetTotalArticlesCount :one
-SELECT COUNT(*) FROM articles;
+-- name: CountArticles :one
+SELECT COUNT(*)
+FROM articles a
+JOIN categories ON a.category_id = categories.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+AND (sqlc.narg(user_id)::uuid IS NULL OR a.user_id = sqlc.narg(user_id))
+AND (sqlc.narg(category_id)::uuid IS NULL OR a.category_id = sqlc.narg(category_id))
+AND (sqlc.narg(category_name)::text IS NULL OR categories.name = sqlc.narg(category_name))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR a.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR a.created_at < sqlc.narg(created_to))
+AND (sqlc.narg(status)::text IS NULL OR a.status = sqlc.narg(status))
+AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = sqlc.narg(tag)
+))
+AND (sqlc.narg(category_slug)::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = sqlc.narg(category_slug)
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+));
+
+-- name: UpdateArticle :one
+WITH old_slug AS (
+    INSERT INTO article_slug_history (slug, article_id, created_at)
+    SELECT slug, id, NOW() FROM articles
+    WHERE id = $1 AND slug <> $6
+    ON CONFLICT (slug) DO NOTHING
+), reclaimed_slug AS (
+    DELETE FROM article_slug_history
+    WHERE slug = $6 AND article_id = $1
+)
+UPDATE articles
+SET category_id = $2, title = $3, body = $4, image_url = $5, slug = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
 
--- name: GetArticlesByUserId :many
-SELECT a.*, users.username
+-- name: UpdateArticleStatus :one
+UPDATE articles
+SET status = sqlc.arg(status),
+    published_at = CASE WHEN sqlc.arg(status) = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+    publish_at = CASE WHEN sqlc.arg(status) = 'scheduled' THEN sqlc.narg(publish_at)::timestamptz ELSE publish_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: PublishDueArticles :many
+UPDATE articles
+SET status = 'published', published_at = publish_at, updated_at = NOW()
+WHERE status = 'scheduled'
+AND id IN (
+    SELECT id FROM articles
+    WHERE status = 'scheduled' AND publish_at <= NOW()
+    ORDER BY publish_at
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id;
+
+-- name: ViewArticle :one
+WITH viewed AS (
+    UPDATE articles SET view_count = view_count + 1
+    WHERE (id = sqlc.narg(id) OR slug = sqlc.narg(slug))
+    AND user_id <> sqlc.arg(viewer_id)
+    AND (
+        status IN ('published', 'archived')
+        OR (status = 'scheduled' AND publish_at <= NOW())
+    )
+    RETURNING id
+)
+SELECT a.*, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position,
+    neighbours.previous_id, neighbours.previous_title, neighbours.previous_status, neighbours.previous_position,
+    neighbours.next_id, neighbours.next_title, neighbours.next_status, neighbours.next_position
 FROM articles a
 JOIN users ON a.user_id = users.id
-WHERE a.user_id = $1
-ORDER BY a.created_at ASC
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+LEFT JOIN LATERAL (
+    SELECT * FROM (
+        SELECT sa.article_id,
+            lag(s.id) OVER w AS previous_id, lag(s.title) OVER w AS previous_title,
+            lag(s.status) OVER w AS previous_status, lag(sa.position) OVER w AS previous_position,
+            lead(s.id) OVER w AS next_id, lead(s.title) OVER w AS next_title,
+            lead(s.status) OVER w AS next_status, lead(sa.position) OVER w AS next_position
+        FROM series_articles sa
+        JOIN articles s ON sa.article_id = s.id
+        WHERE sa.series_id = series_articles.series_id
+        AND (
+            s.status IN ('published', 'archived')
+            OR (s.status = 'scheduled' AND s.publish_at <= NOW())
+            OR s.user_id = sqlc.arg(viewer_id)
+        )
+        WINDOW w AS (ORDER BY sa.position)
+    ) series_neighbours
+    WHERE series_neighbours.article_id = a.id
+) neighbours ON true
+WHERE (a.id = sqlc.narg(id) OR a.slug = sqlc.narg(slug))
+AND (
+    a.status IN ('published', 'archived')
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+);
+
+-- name: GetArticlesAfter :many
+SELECT a.*, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
+FROM articles a
+JOIN users ON a.user_id = users.id
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+AND (sqlc.narg(user_id)::uuid IS NULL OR a.user_id = sqlc.narg(user_id))
+AND (sqlc.narg(category_id)::uuid IS NULL OR a.category_id = sqlc.narg(category_id))
+AND (sqlc.narg(category_name)::text IS NULL OR categories.name = sqlc.narg(category_name))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR a.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR a.created_at < sqlc.narg(created_to))
+AND (sqlc.narg(status)::text IS NULL OR a.status = sqlc.narg(status))
+AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = sqlc.narg(tag)
+))
+AND (sqlc.narg(category_slug)::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = sqlc.narg(category_slug)
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+AND (
+    sqlc.narg(cursor_created_at)::timestamp IS NULL
+    OR (a.created_at, a.id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY a.created_at ASC, a.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetArticlesBefore :many
+SELECT a.*, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position
+FROM articles a
+JOIN users ON a.user_id = users.id
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+WHERE (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+AND (sqlc.narg(user_id)::uuid IS NULL OR a.user_id = sqlc.narg(user_id))
+AND (sqlc.narg(category_id)::uuid IS NULL OR a.category_id = sqlc.narg(category_id))
+AND (sqlc.narg(category_name)::text IS NULL OR categories.name = sqlc.narg(category_name))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR a.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR a.created_at < sqlc.narg(created_to))
+AND (sqlc.narg(status)::text IS NULL OR a.status = sqlc.narg(status))
+AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+    SELECT 1 FROM article_tags
+    JOIN tags ON article_tags.tag_id = tags.id
+    WHERE article_tags.article_id = a.id AND tags.slug = sqlc.narg(tag)
+))
+AND (sqlc.narg(category_slug)::text IS NULL OR a.category_id IN (
+    WITH RECURSIVE category_tree AS (
+        SELECT categories.id FROM categories WHERE categories.slug = sqlc.narg(category_slug)
+        UNION ALL
+        SELECT child.id FROM categories child JOIN category_tree ON child.parent_id = category_tree.id
+    )
+    SELECT category_tree.id FROM category_tree
+))
+AND (
+    sqlc.narg(cursor_created_at)::timestamp IS NULL
+    OR (a.created_at, a.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.arg('limit');
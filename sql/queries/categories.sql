@@ -1,5 +1,61 @@
 -- name: GetCategories :many
-SELECT * FROM categories ORDER BY name ASC;
+SELECT * FROM categories ORDER BY position ASC, name ASC;
+
+-- name: GetCategoriesWithArticleCounts :many
+SELECT c.*, COUNT(a.id) AS article_count
+FROM categories c
+LEFT JOIN articles a ON a.category_id = c.id
+AND (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+)
+GROUP BY c.id
+ORDER BY c.position ASC, c.name ASC;
 
 -- name: GetCategoryByID :one
 SELECT * FROM categories WHERE id = $1;
+
+-- name: GetCategoryBySlug :one
+SELECT * FROM categories WHERE slug = $1;
+
+-- name: CreateCategory :one
+INSERT INTO categories (id, name, position, parent_id, slug, description)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM categories),
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: UpdateCategory :one
+UPDATE categories SET name = $2, parent_id = $3, slug = $4, description = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: ReorderCategories :exec
+UPDATE categories
+SET position = new_order.position
+FROM unnest(sqlc.arg(ids)::uuid[]) WITH ORDINALITY AS new_order(id, position)
+WHERE categories.id = new_order.id;
+
+-- name: DeleteCategory :execrows
+WITH reparented AS (
+    UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = sqlc.arg(id))
+    WHERE parent_id = sqlc.arg(id)
+)
+DELETE FROM categories
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteCategoryReassigningArticles :execrows
+WITH reassigned AS (
+    UPDATE articles SET category_id = sqlc.arg(reassign_to), updated_at = NOW()
+    WHERE category_id = sqlc.arg(id)
+), reparented AS (
+    UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = sqlc.arg(id))
+    WHERE parent_id = sqlc.arg(id)
+)
+DELETE FROM categories
+WHERE id = sqlc.arg(id);
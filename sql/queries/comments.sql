@@ -0,0 +1,54 @@
+-- name: CreateComment :one
+INSERT INTO comments (id, created_at, updated_at, article_id, user_id, parent_id, body)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: GetComment :one
+SELECT c.*, users.username
+FROM comments c
+JOIN users ON c.user_id = users.id
+WHERE c.id = $1;
+
+-- name: GetRootComments :many
+SELECT c.*, users.username
+FROM comments c
+JOIN users ON c.user_id = users.id
+WHERE c.article_id = $1 AND c.parent_id IS NULL
+ORDER BY c.created_at ASC
+LIMIT $2 OFFSET $3;
+
+-- name: GetTotalRootCommentsCount :one
+SELECT COUNT(*) FROM comments
+WHERE article_id = $1 AND parent_id IS NULL;
+
+-- name: GetCommentReplies :many
+WITH RECURSIVE thread AS (
+    SELECT id, created_at, updated_at, article_id, user_id, parent_id, body
+    FROM comments
+    WHERE comments.parent_id = ANY(sqlc.arg(root_ids)::uuid[])
+    UNION ALL
+    SELECT c.id, c.created_at, c.updated_at, c.article_id, c.user_id, c.parent_id, c.body
+    FROM comments c
+    JOIN thread ON c.parent_id = thread.id
+)
+SELECT thread.id, thread.created_at, thread.updated_at, thread.article_id, thread.user_id, thread.parent_id, thread.body, users.username
+FROM thread
+JOIN users ON thread.user_id = users.id
+ORDER BY thread.created_at ASC;
+
+-- name: UpdateComment :one
+UPDATE comments SET body = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteComment :exec
+DELETE FROM comments
+WHERE id = $1;
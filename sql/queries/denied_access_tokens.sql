@@ -0,0 +1,22 @@
+-- name: DenyAccessToken :exec
+WITH expired AS (
+    DELETE FROM denied_access_tokens
+    WHERE expires_at <= NOW()
+)
+INSERT INTO denied_access_tokens (jti, expires_at)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsAccessTokenActive :one
+SELECT NOT EXISTS (
+    SELECT 1 FROM denied_access_tokens
+    WHERE jti = $1
+) AND EXISTS (
+    SELECT 1 FROM refresh_tokens
+    WHERE session_id = $2
+    AND revoked_at IS NULL
+    AND expires_at > NOW()
+) AS active;
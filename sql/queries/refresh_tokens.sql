@@ -1,22 +1,39 @@
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at)
+INSERT INTO refresh_tokens (token_hash, created_at, updated_at, user_id, expires_at, session_id)
 VALUES (
     $1,
     NOW(),
     NOW(),
     $2,
-    $3
+    $3,
+    $4
 )
 RETURNING *;
 
 -- name: RevokeRefreshToken :one
-UPDATE refresh_tokens SET revoked_at = NOW()
-WHERE token = $1
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE token_hash = $1
 RETURNING *;
 
--- name: GetUserFromRefreshToken :one
-SELECT users.* FROM users
-JOIN refresh_tokens ON users.id = refresh_tokens.user_id
-WHERE refresh_tokens.token = $1
-AND revoked_at IS NULL
-AND expires_at > NOW();
\ No newline at end of file
+-- name: RotateRefreshToken :one
+WITH rotated AS (
+    UPDATE refresh_tokens
+    SET revoked_at = NOW(), updated_at = NOW(), replaced_by = sqlc.arg(new_token_hash)
+    WHERE token_hash = sqlc.arg(token_hash)
+    AND revoked_at IS NULL
+    AND expires_at > NOW()
+    RETURNING user_id, session_id
+)
+INSERT INTO refresh_tokens (token_hash, created_at, updated_at, user_id, expires_at, session_id)
+SELECT sqlc.arg(new_token_hash), NOW(), NOW(), rotated.user_id, sqlc.arg(expires_at), rotated.session_id
+FROM rotated
+RETURNING *;
+
+-- name: RevokeReusedRefreshTokenFamily :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE session_id = (
+    SELECT session_id FROM refresh_tokens
+    WHERE refresh_tokens.token_hash = $1
+    AND replaced_by IS NOT NULL
+)
+AND revoked_at IS NULL;
@@ -0,0 +1,48 @@
+-- name: SearchArticles :many
+SELECT a.*, users.username, categories.name AS category_name,
+    ARRAY(SELECT tags.slug FROM article_tags JOIN tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id = a.id ORDER BY tags.slug) AS tags,
+    series.id AS series_id, series.title AS series_title, series_articles.position AS series_position,
+    ts_rank(a.search_vector, q) AS rank,
+    ts_headline('english', a.title, q, 'StartSel=' || chr(57344) || ', StopSel=' || chr(57345) || ', HighlightAll=true') AS title_highlight,
+    ts_headline(
+        'english',
+        regexp_replace(regexp_replace(article_body_text(a.body, '{heading,paragraph,quote,list}'), '<br\s*/?>', ' ', 'gi'), '<[^>]*>', '', 'g'),
+        q,
+        'StartSel=' || chr(57344) || ', StopSel=' || chr(57345) || ', MaxFragments=2, MaxWords=30, MinWords=10'
+    ) AS snippet
+FROM articles a
+JOIN users ON a.user_id = users.id
+JOIN categories ON a.category_id = categories.id
+LEFT JOIN series_articles ON series_articles.article_id = a.id
+LEFT JOIN series ON series_articles.series_id = series.id
+CROSS JOIN websearch_to_tsquery('english', sqlc.arg(query)) q
+WHERE a.search_vector @@ q
+AND (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+AND (sqlc.narg(category_id)::uuid IS NULL OR a.category_id = sqlc.narg(category_id))
+AND (sqlc.narg(category_name)::text IS NULL OR categories.name = sqlc.narg(category_name))
+AND (sqlc.narg(author_id)::uuid IS NULL OR a.user_id = sqlc.narg(author_id))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR a.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR a.created_at < sqlc.narg(created_to))
+ORDER BY rank DESC, a.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetTotalSearchResultsCount :one
+SELECT COUNT(*)
+FROM articles a
+JOIN categories ON a.category_id = categories.id
+CROSS JOIN websearch_to_tsquery('english', sqlc.arg(query)) q
+WHERE a.search_vector @@ q
+AND (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+AND (sqlc.narg(category_id)::uuid IS NULL OR a.category_id = sqlc.narg(category_id))
+AND (sqlc.narg(category_name)::text IS NULL OR categories.name = sqlc.narg(category_name))
+AND (sqlc.narg(author_id)::uuid IS NULL OR a.user_id = sqlc.narg(author_id))
+AND (sqlc.narg(created_from)::timestamp IS NULL OR a.created_at >= sqlc.narg(created_from))
+AND (sqlc.narg(created_to)::timestamp IS NULL OR a.created_at < sqlc.narg(created_to));
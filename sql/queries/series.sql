@@ -0,0 +1,50 @@
+-- name: CreateSeries :one
+INSERT INTO series (id, created_at, user_id, title, description)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetSeries :one
+SELECT * FROM series WHERE id = $1;
+
+-- name: GetSeriesArticles :many
+SELECT a.id, a.title, a.status, series_articles.position
+FROM series_articles
+JOIN articles a ON series_articles.article_id = a.id
+WHERE series_articles.series_id = sqlc.arg(series_id)
+AND (
+    a.status = 'published'
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+    OR a.user_id = sqlc.arg(viewer_id)
+)
+ORDER BY series_articles.position ASC;
+
+-- name: AddSeriesArticle :exec
+INSERT INTO series_articles (series_id, article_id, position)
+VALUES (
+    $1,
+    $2,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM series_articles WHERE series_id = $1)
+);
+
+-- name: ReorderSeriesArticles :exec
+UPDATE series_articles
+SET position = new_order.position
+FROM unnest(sqlc.arg(article_ids)::uuid[]) WITH ORDINALITY AS new_order(article_id, position)
+WHERE series_articles.series_id = sqlc.arg(series_id)
+AND series_articles.article_id = new_order.article_id;
+
+-- name: RemoveSeriesArticle :exec
+WITH removed AS (
+    DELETE FROM series_articles
+    WHERE series_id = sqlc.arg(series_id) AND article_id = sqlc.arg(article_id)
+    RETURNING position
+)
+UPDATE series_articles SET position = position - 1
+WHERE series_id = sqlc.arg(series_id)
+AND position > (SELECT position FROM removed);
@@ -0,0 +1,51 @@
+-- name: CreateSession :one
+INSERT INTO sessions (id, created_at, user_id, user_agent, ip, last_used_at)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    NOW()
+)
+RETURNING *;
+
+-- name: UpdateSessionActivity :exec
+UPDATE sessions SET last_used_at = NOW(), user_agent = $2, ip = $3
+WHERE id = $1;
+
+-- name: GetActiveSessions :many
+SELECT * FROM sessions
+WHERE user_id = $1
+AND EXISTS (
+    SELECT 1 FROM refresh_tokens
+    WHERE refresh_tokens.session_id = sessions.id
+    AND refresh_tokens.revoked_at IS NULL
+    AND refresh_tokens.expires_at > NOW()
+)
+ORDER BY last_used_at DESC;
+
+-- name: RevokeSession :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE session_id = $1
+AND user_id = $2
+AND revoked_at IS NULL;
+
+-- name: RevokeOtherSessions :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1
+AND session_id <> $2
+AND revoked_at IS NULL;
+
+-- name: RevokeAllSessions :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1
+AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenSession :execrows
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE session_id = (
+    SELECT session_id FROM refresh_tokens
+    WHERE refresh_tokens.token_hash = $1
+)
+AND revoked_at IS NULL;
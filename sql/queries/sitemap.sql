@@ -0,0 +1,21 @@
+-- name: CountSitemapURLs :one
+SELECT (SELECT COUNT(*) FROM categories) + (
+    SELECT COUNT(*) FROM articles
+    WHERE status IN ('published', 'archived')
+    OR (status = 'scheduled' AND publish_at <= NOW())
+) AS count;
+
+-- name: GetSitemapURLs :many
+SELECT kind, slug, lastmod FROM (
+    SELECT 'category' AS kind, c.slug, MAX(a.updated_at) AS lastmod, 0 AS kind_order, NULL::timestamp AS created_at, c.id
+    FROM categories c
+    LEFT JOIN articles a ON a.category_id = c.id AND a.status = 'published'
+    GROUP BY c.id
+    UNION ALL
+    SELECT 'article' AS kind, a.slug, a.updated_at AS lastmod, 1 AS kind_order, a.created_at, a.id
+    FROM articles a
+    WHERE a.status IN ('published', 'archived')
+    OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+) urls
+ORDER BY kind_order, created_at, slug, id
+LIMIT $1 OFFSET $2;
@@ -0,0 +1,24 @@
+-- name: AddArticleTags :exec
+WITH article_tag_ids AS (
+    INSERT INTO tags (id, created_at, name, slug)
+    SELECT gen_random_uuid(), NOW(), new_tags.name, new_tags.slug
+    FROM unnest(sqlc.arg(names)::text[], sqlc.arg(slugs)::text[]) AS new_tags(name, slug)
+    ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+    RETURNING id
+)
+INSERT INTO article_tags (article_id, tag_id)
+SELECT sqlc.arg(article_id)::uuid, id FROM article_tag_ids
+ON CONFLICT DO NOTHING;
+
+-- name: GetTags :many
+SELECT t.*, COUNT(a.id) AS article_count
+FROM tags t
+JOIN article_tags ON article_tags.tag_id = t.id
+JOIN articles a ON article_tags.article_id = a.id
+WHERE a.status = 'published'
+OR (a.status = 'scheduled' AND a.publish_at <= NOW())
+GROUP BY t.id
+ORDER BY article_count DESC, t.slug ASC;
+
+-- name: GetTagBySlug :one
+SELECT * FROM tags WHERE slug = $1;
@@ -21,4 +21,8 @@ RETURNING *;
 
 -- name: GetUserByID :one
 SELECT * FROM users
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET hashed_password = $2, updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE comments (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL
+);
+
+CREATE INDEX comments_article_id_idx ON comments (article_id, created_at);
+CREATE INDEX comments_parent_id_idx ON comments (parent_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS comments;
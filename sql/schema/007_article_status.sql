@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE articles
+ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+    CONSTRAINT articles_status_check CHECK (status IN ('draft', 'published', 'archived')),
+ADD COLUMN published_at TIMESTAMP;
+
+-- existing articles were published the moment they were created
+UPDATE articles SET published_at = created_at;
+
+CREATE INDEX articles_status_idx ON articles (status);
+
+-- +goose Down
+ALTER TABLE articles
+DROP COLUMN published_at,
+DROP COLUMN status;
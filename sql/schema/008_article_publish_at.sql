@@ -0,0 +1,24 @@
+-- +goose Up
+-- publish_at is compared with NOW(), which is in the session time zone, so it is stored as a point in time
+-- and scheduled articles go live on time whatever the time zone of the database
+ALTER TABLE articles
+ADD COLUMN publish_at TIMESTAMPTZ;
+
+ALTER TABLE articles
+DROP CONSTRAINT articles_status_check,
+ADD CONSTRAINT articles_status_check CHECK (status IN ('draft', 'scheduled', 'published', 'archived'));
+
+-- the scheduler only ever looks for scheduled articles that are due
+CREATE INDEX articles_scheduled_publish_at_idx ON articles (publish_at) WHERE status = 'scheduled';
+
+-- +goose Down
+DROP INDEX IF EXISTS articles_scheduled_publish_at_idx;
+
+UPDATE articles SET status = 'draft' WHERE status = 'scheduled';
+
+ALTER TABLE articles
+DROP CONSTRAINT articles_status_check,
+ADD CONSTRAINT articles_status_check CHECK (status IN ('draft', 'published', 'archived'));
+
+ALTER TABLE articles
+DROP COLUMN publish_at;
@@ -0,0 +1,96 @@
+-- +goose Up
+CREATE TABLE article_revisions (
+    id UUID PRIMARY KEY,
+    article_id UUID NOT NULL, -- no foreign key, revisions outlive deleted articles so they can be restored
+    revision_number INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    event TEXT NOT NULL CHECK (event IN ('created', 'updated', 'deleted')),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    body JSONB NOT NULL,
+    image_url TEXT,
+    status TEXT NOT NULL,
+    UNIQUE (article_id, revision_number)
+);
+
+-- every existing article starts out with a single revision
+INSERT INTO article_revisions (id, article_id, revision_number, created_at, event, user_id, category_id, title, body, image_url, status)
+SELECT gen_random_uuid(), id, 1, updated_at, 'created', user_id, category_id, title, body, image_url, status
+FROM articles;
+
+-- +goose StatementBegin
+CREATE FUNCTION record_article_revision() RETURNS trigger AS $$
+DECLARE
+    snapshot articles%ROWTYPE;
+    revision_event TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        snapshot := OLD;
+        revision_event := 'deleted';
+        -- articles removed because their author was deleted have nobody left to restore them
+        IF NOT EXISTS (SELECT 1 FROM users WHERE id = OLD.user_id) THEN
+            RETURN OLD;
+        END IF;
+    ELSIF TG_OP = 'UPDATE' THEN
+        snapshot := NEW;
+        revision_event := 'updated';
+    ELSE
+        snapshot := NEW;
+        revision_event := 'created';
+    END IF;
+
+    INSERT INTO article_revisions (id, article_id, revision_number, created_at, event, user_id, category_id, title, body, image_url, status)
+    VALUES (
+        gen_random_uuid(),
+        snapshot.id,
+        COALESCE((SELECT MAX(revision_number) FROM article_revisions WHERE article_id = snapshot.id), 0) + 1,
+        NOW(),
+        revision_event,
+        snapshot.user_id,
+        snapshot.category_id,
+        snapshot.title,
+        snapshot.body,
+        snapshot.image_url,
+        snapshot.status
+    );
+    RETURN snapshot;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION prevent_article_revision_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'article revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER articles_record_revision_insert
+AFTER INSERT OR DELETE ON articles
+FOR EACH ROW EXECUTE FUNCTION record_article_revision();
+
+-- only changes to the article content create a revision, not bookkeeping columns
+CREATE TRIGGER articles_record_revision_update
+AFTER UPDATE ON articles
+FOR EACH ROW
+WHEN (
+    OLD.category_id IS DISTINCT FROM NEW.category_id
+    OR OLD.title IS DISTINCT FROM NEW.title
+    OR OLD.body IS DISTINCT FROM NEW.body
+    OR OLD.image_url IS DISTINCT FROM NEW.image_url
+    OR OLD.status IS DISTINCT FROM NEW.status
+)
+EXECUTE FUNCTION record_article_revision();
+
+CREATE TRIGGER article_revisions_immutable
+BEFORE UPDATE ON article_revisions
+FOR EACH ROW EXECUTE FUNCTION prevent_article_revision_update();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS articles_record_revision_update ON articles;
+DROP TRIGGER IF EXISTS articles_record_revision_insert ON articles;
+DROP TABLE IF EXISTS article_revisions;
+DROP FUNCTION IF EXISTS prevent_article_revision_update();
+DROP FUNCTION IF EXISTS record_article_revision();
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE articles
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(jsonb_to_tsvector('english', COALESCE(body->'headers', '{}'::jsonb), '["string"]'), 'B') ||
+    setweight(jsonb_to_tsvector('english', COALESCE(body->'content', '{}'::jsonb), '["string"]'), 'C')
+) STORED;
+
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS articles_search_vector_idx;
+
+ALTER TABLE articles
+DROP COLUMN search_vector;
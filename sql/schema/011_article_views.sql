@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE articles
+ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX articles_created_at_idx ON articles (created_at);
+CREATE INDEX articles_view_count_idx ON articles (view_count);
+
+-- +goose Down
+DROP INDEX IF EXISTS articles_view_count_idx;
+DROP INDEX IF EXISTS articles_created_at_idx;
+
+ALTER TABLE articles
+DROP COLUMN view_count;
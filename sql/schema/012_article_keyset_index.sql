@@ -0,0 +1,8 @@
+-- +goose Up
+-- cursor pagination walks articles in (created_at, id) order
+DROP INDEX IF EXISTS articles_created_at_idx;
+CREATE INDEX articles_created_at_id_idx ON articles (created_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS articles_created_at_id_idx;
+CREATE INDEX articles_created_at_idx ON articles (created_at);
@@ -0,0 +1,16 @@
+-- +goose Up
+-- admins manage site wide content such as categories, grant the flag with
+-- UPDATE users SET is_admin = TRUE WHERE email = '...';
+ALTER TABLE users
+ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- display order of the categories, lowest first
+ALTER TABLE categories
+ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE categories
+DROP COLUMN position;
+
+ALTER TABLE users
+DROP COLUMN is_admin;
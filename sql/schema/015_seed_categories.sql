@@ -0,0 +1,17 @@
+-- +goose Up
+-- default categories for new deployments, existing categories with the same name are left alone
+INSERT INTO categories (id, name, position)
+VALUES
+    (gen_random_uuid(), 'General', 1),
+    (gen_random_uuid(), 'Technology', 2),
+    (gen_random_uuid(), 'Science', 3),
+    (gen_random_uuid(), 'Culture', 4),
+    (gen_random_uuid(), 'Sports', 5),
+    (gen_random_uuid(), 'Travel', 6)
+ON CONFLICT (name) DO NOTHING;
+
+-- +goose Down
+-- only remove the seeded categories that are still unused
+DELETE FROM categories
+WHERE name IN ('General', 'Technology', 'Science', 'Culture', 'Sports', 'Travel')
+AND NOT EXISTS (SELECT 1 FROM articles WHERE articles.category_id = categories.id);
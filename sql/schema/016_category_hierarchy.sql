@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE categories
+ADD COLUMN parent_id UUID REFERENCES categories(id),
+ADD COLUMN slug TEXT,
+ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+-- derive slugs for the existing categories, names that collapse to the same slug keep them apart with part of their ID
+UPDATE categories
+SET slug = trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE categories c
+SET slug = concat_ws('-', NULLIF(c.slug, ''), left(c.id::text, 8))
+WHERE c.slug = ''
+OR EXISTS (SELECT 1 FROM categories other WHERE other.slug = c.slug AND other.id < c.id);
+
+ALTER TABLE categories
+ALTER COLUMN slug SET NOT NULL,
+ADD CONSTRAINT categories_slug_key UNIQUE (slug);
+
+CREATE INDEX categories_parent_id_idx ON categories (parent_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS categories_parent_id_idx;
+
+ALTER TABLE categories
+DROP COLUMN description,
+DROP COLUMN slug,
+DROP COLUMN parent_id;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE series (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX series_user_id_idx ON series (user_id);
+
+-- an article belongs to at most one series, position orders the articles within it
+CREATE TABLE series_articles (
+    series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL UNIQUE REFERENCES articles(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (series_id, article_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS series_articles;
+DROP TABLE IF EXISTS series;
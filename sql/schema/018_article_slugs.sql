@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE articles ADD COLUMN slug TEXT;
+
+-- give existing articles a slug derived from their title, articles sharing a title get their id appended
+WITH derived AS (
+    SELECT id, COALESCE(NULLIF(left(trim(BOTH '-' FROM regexp_replace(lower(title), '[^[:alnum:]]+', '-', 'g')), 80), ''), 'article') AS base,
+        row_number() OVER (PARTITION BY COALESCE(NULLIF(left(trim(BOTH '-' FROM regexp_replace(lower(title), '[^[:alnum:]]+', '-', 'g')), 80), ''), 'article') ORDER BY created_at, id) AS n
+    FROM articles
+)
+UPDATE articles
+SET slug = CASE WHEN derived.n = 1 THEN derived.base ELSE derived.base || '-' || left(articles.id::text, 8) END
+FROM derived
+WHERE articles.id = derived.id;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE articles ADD CONSTRAINT articles_slug_key UNIQUE (slug);
+
+-- slugs an article had before its title changed, they keep redirecting to the article
+CREATE TABLE article_slug_history (
+    slug TEXT PRIMARY KEY,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX article_slug_history_article_id_idx ON article_slug_history (article_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS article_slug_history;
+ALTER TABLE articles DROP COLUMN IF EXISTS slug;
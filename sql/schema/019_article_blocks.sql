@@ -0,0 +1,100 @@
+-- +goose Up
+-- article bodies become an ordered list of blocks, {"version": 2, "blocks": [{"type": "paragraph", "text": "..."}]},
+-- the legacy {"headers": {}, "content": {}, "images": {}} bodies are converted section by section,
+-- introduction, mainBody and conclusion first and any other section after them by name,
+-- the conversion stays around for restoring revisions recorded before this migration
+
+-- +goose StatementBegin
+CREATE FUNCTION article_blocks_from_legacy_body(body JSONB) RETURNS JSONB AS $$
+DECLARE
+    headers JSONB := CASE WHEN jsonb_typeof(body->'headers') = 'object' THEN body->'headers' ELSE '{}' END;
+    content JSONB := CASE WHEN jsonb_typeof(body->'content') = 'object' THEN body->'content' ELSE '{}' END;
+    images JSONB := CASE WHEN jsonb_typeof(body->'images') = 'object' THEN body->'images' ELSE '{}' END;
+    blocks JSONB := '[]';
+    section TEXT;
+    paragraph JSONB;
+BEGIN
+    FOR section IN
+        SELECT key FROM (
+            SELECT jsonb_object_keys(headers) AS key
+            UNION SELECT jsonb_object_keys(content)
+            UNION SELECT jsonb_object_keys(images)
+        ) sections
+        ORDER BY array_position(ARRAY['introduction', 'mainBody', 'conclusion'], key), key COLLATE "C"
+    LOOP
+        IF COALESCE(headers->>section, '') <> '' THEN
+            blocks := blocks || jsonb_build_object('type', 'heading', 'level', 2, 'text', headers->>section);
+        END IF;
+        IF COALESCE(images->>section, '') <> '' THEN
+            blocks := blocks || jsonb_build_object('type', 'image', 'url', images->>section);
+        END IF;
+        -- a string is a single paragraph and every element of an array is one, anything else is kept as its JSON text
+        FOR paragraph IN
+            SELECT value FROM jsonb_array_elements(
+                CASE WHEN jsonb_typeof(content->section) = 'array' THEN content->section ELSE jsonb_build_array(content->section) END
+            )
+        LOOP
+            IF paragraph IS NULL OR jsonb_typeof(paragraph) = 'null' OR paragraph = '""' THEN
+                CONTINUE;
+            END IF;
+            blocks := blocks || jsonb_build_object(
+                'type', 'paragraph',
+                'text', CASE WHEN jsonb_typeof(paragraph) = 'string' THEN paragraph #>> '{}' ELSE paragraph::text END
+            );
+        END LOOP;
+    END LOOP;
+    RETURN jsonb_build_object('version', 2, 'blocks', blocks);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+-- +goose StatementEnd
+
+-- text of the blocks of the given types, used for the search vector and the search snippets
+-- +goose StatementBegin
+CREATE FUNCTION article_body_text(body JSONB, block_types TEXT[]) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(
+        concat_ws(' ',
+            block->>'text',
+            block->>'caption',
+            block->>'code',
+            (SELECT string_agg(item, ' ') FROM jsonb_array_elements_text(
+                CASE WHEN jsonb_typeof(block->'items') = 'array' THEN block->'items' ELSE '[]' END
+            ) AS item)
+        ),
+        ' ' ORDER BY position
+    ), '')
+    FROM jsonb_array_elements(
+        CASE WHEN jsonb_typeof(body->'blocks') = 'array' THEN body->'blocks' ELSE '[]' END
+    ) WITH ORDINALITY AS blocks(block, position)
+    WHERE block->>'type' = ANY(block_types)
+$$ LANGUAGE sql IMMUTABLE;
+-- +goose StatementEnd
+
+-- converting the bodies is not an edit, so it does not record revisions, older revisions keep their legacy bodies
+ALTER TABLE articles DISABLE TRIGGER articles_record_revision_update;
+UPDATE articles SET body = article_blocks_from_legacy_body(body) WHERE NOT body ? 'version';
+ALTER TABLE articles ENABLE TRIGGER articles_record_revision_update;
+
+ALTER TABLE articles DROP COLUMN search_vector;
+ALTER TABLE articles
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', article_body_text(body, '{heading}')), 'B') ||
+    setweight(to_tsvector('english', article_body_text(body, '{paragraph,quote,list,code,image,embed}')), 'C')
+) STORED;
+
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
+
+-- +goose Down
+-- the bodies keep their block format, code from before this migration ignores the unknown keys
+ALTER TABLE articles DROP COLUMN search_vector;
+ALTER TABLE articles
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(jsonb_to_tsvector('english', COALESCE(body->'headers', '{}'::jsonb), '["string"]'), 'B') ||
+    setweight(jsonb_to_tsvector('english', COALESCE(body->'content', '{}'::jsonb), '["string"]'), 'C')
+) STORED;
+
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
+
+DROP FUNCTION IF EXISTS article_body_text(JSONB, TEXT[]);
+DROP FUNCTION IF EXISTS article_blocks_from_legacy_body(JSONB);
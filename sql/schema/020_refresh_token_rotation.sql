@@ -0,0 +1,20 @@
+-- +goose Up
+-- refresh tokens are only stored as the hex encoded SHA-256 hash of the token the client holds
+UPDATE refresh_tokens SET token = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+ALTER TABLE refresh_tokens RENAME COLUMN token TO token_hash;
+
+-- every refresh replaces the token with a new one of the same family, a family starts at login or sign up.
+-- replaced_by holds the hash of the successor, so a replaced token that shows up again is recognized as reused
+ALTER TABLE refresh_tokens ADD COLUMN family_id UUID NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE refresh_tokens ALTER COLUMN family_id DROP DEFAULT;
+ALTER TABLE refresh_tokens ADD COLUMN replaced_by TEXT;
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+-- +goose Down
+DROP INDEX refresh_tokens_family_id_idx;
+ALTER TABLE refresh_tokens DROP COLUMN replaced_by;
+ALTER TABLE refresh_tokens DROP COLUMN family_id;
+ALTER TABLE refresh_tokens RENAME COLUMN token_hash TO token;
+-- the hashes cannot be turned back into tokens, everyone has to log in again
+DELETE FROM refresh_tokens;
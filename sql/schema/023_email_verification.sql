@@ -0,0 +1,24 @@
+-- +goose Up
+-- NULL until the user follows the link mailed to their address
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- accounts from before email verification count as verified, otherwise REQUIRE_EMAIL_VERIFICATION would lock
+-- existing authors out of creating articles until they follow a link they were never sent
+UPDATE users SET email_verified_at = created_at;
+
+-- tokens mailed to confirm an address, email is the address the token confirms: the current one of the user,
+-- or the one they want to switch to, which only replaces the current one once it is confirmed
+CREATE TABLE email_verification_tokens (
+    token_hash TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX email_verification_tokens_user_id_idx ON email_verification_tokens (user_id);
+
+-- +goose Down
+DROP TABLE email_verification_tokens;
+ALTER TABLE users DROP COLUMN email_verified_at;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- access tokens that were logged out before they expired, by their jti claim. Access tokens are short lived,
+-- so a row is only kept until its token would have expired anyway.
+CREATE TABLE denied_access_tokens (
+    jti TEXT PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX denied_access_tokens_expires_at_idx ON denied_access_tokens (expires_at);
+
+-- +goose Down
+DROP TABLE denied_access_tokens;